@@ -0,0 +1,190 @@
+package errorx
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	errorIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_.]*$`)
+	argNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	catalogMu sync.RWMutex
+	catalog   = make(map[string]CatalogEntry)
+)
+
+/*
+	Catalog - declares the errors of a service in one place. Status is mapped from the code by StatusMapping if it is empty.
+	For example;
+		errors:
+			- id: user_not_found
+			  code: 404001
+			  status: 404
+			  message: "user not found, user ID : %d"
+			  severity: warning
+			  args:
+				- name: userID
+				  type: int64
+*/
+type Catalog struct {
+	StatusMapping *StatusMappingCfg `json:"status_mapping" yaml:"status_mapping"` // default is the global StatusMapping
+	Errors        []CatalogEntry    `json:"errors" yaml:"errors"`
+}
+
+type CatalogEntry struct {
+	ID       string       `json:"id" yaml:"id"`
+	Code     int          `json:"code" yaml:"code"`
+	Status   int          `json:"status" yaml:"status"`
+	Message  string       `json:"message" yaml:"message"`
+	Severity Severity     `json:"severity" yaml:"severity"`
+	Args     []CatalogArg `json:"args" yaml:"args"`
+}
+
+type CatalogArg struct {
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"` // Go type of constructor argument, default is interface{}
+}
+
+// LoadCatalog reads catalog from JSON or YAML file, the format is decided by file extension.
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cat Catalog
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, &cat)
+	default:
+		err = json.Unmarshal(data, &cat)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cat, cat.Validate()
+}
+
+// Validate checks the required fields and the uniqueness of IDs and codes. Defaults are filled in.
+func (cat *Catalog) Validate() error {
+	mapping := GetStatusMapping()
+	if cat.StatusMapping != nil {
+		var err error
+		mapping, err = NewStatusMapping(cat.StatusMapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	ids := make(map[string]bool)
+	codes := make(map[int]string)
+	for i := range cat.Errors {
+		entry := &cat.Errors[i]
+		if !errorIDPattern.MatchString(entry.ID) {
+			return NewErrorX("invalid error ID '%s', ID must be lower case letters, digits, '_' and '.'", entry.ID)
+		}
+		if ids[entry.ID] {
+			return NewErrorX("duplicate error ID '%s'", entry.ID)
+		}
+		ids[entry.ID] = true
+
+		if entry.Code <= 0 {
+			return NewErrorX("error code of '%s' must be positive", entry.ID)
+		}
+		if id, ok := codes[entry.Code]; ok {
+			return NewErrorX("duplicate error code %d of '%s' and '%s'", entry.Code, id, entry.ID)
+		}
+		codes[entry.Code] = entry.ID
+
+		if entry.Message == "" {
+			return NewErrorX("message of '%s' is required", entry.ID)
+		}
+		if entry.Status == 0 {
+			status, ok := mapping.StatusCode(entry.Code)
+			if !ok {
+				return NewErrorX("http status of '%s' is required, code %d is not mapped to http status", entry.ID, entry.Code)
+			}
+			entry.Status = status
+		}
+		if entry.Status < 100 || entry.Status > 599 {
+			return NewErrorX("invalid http status %d of '%s'", entry.Status, entry.ID)
+		}
+		if entry.Severity == "" {
+			entry.Severity = SeverityError
+		}
+		if !entry.Severity.valid() {
+			return NewErrorX("invalid severity '%s' of '%s'", entry.Severity, entry.ID)
+		}
+		for _, arg := range entry.Args {
+			if !argNamePattern.MatchString(arg.Name) {
+				return NewErrorX("invalid argument name '%s' of '%s'", arg.Name, entry.ID)
+			}
+		}
+	}
+	return nil
+}
+
+/*
+	RegisterCatalog - adds the entries into the global catalog so that they can be found by Lookup.
+					  Generated code registers its entries in init().
+*/
+func RegisterCatalog(cat *Catalog) error {
+	err := cat.Validate()
+	if err != nil {
+		return err
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	for _, entry := range cat.Errors {
+		existing, ok := catalog[entry.ID]
+		if ok && existing.Code != entry.Code {
+			return NewErrorX("error ID '%s' is already registered with code %d", entry.ID, existing.Code)
+		}
+		for id, registered := range catalog {
+			if id != entry.ID && registered.Code == entry.Code {
+				return NewErrorX("error code %d of '%s' is already registered by '%s'", entry.Code, entry.ID, id)
+			}
+		}
+	}
+	for _, entry := range cat.Errors {
+		catalog[entry.ID] = entry
+	}
+	return nil
+}
+
+func MustRegisterCatalog(cat *Catalog) {
+	err := RegisterCatalog(cat)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the catalog entry of the error ID.
+func Lookup(id string) (CatalogEntry, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	entry, ok := catalog[id]
+	return entry, ok
+}
+
+// CatalogEntries returns all the registered entries ordered by code.
+func CatalogEntries() []CatalogEntry {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	entries := make([]CatalogEntry, 0, len(catalog))
+	for _, entry := range catalog {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+	return entries
+}
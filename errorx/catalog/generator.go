@@ -0,0 +1,198 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+)
+
+const (
+	errorxImport string = "github.com/kyawmyintthein/orange-contrib/errorx"
+)
+
+type GeneratorCfg struct {
+	PackageName string
+}
+
+/*
+	Generate - emits Go source of typed errors declared in the catalog. For each entry, it generates
+			   ID and code constants, error type, constructor with typed arguments and a sentinel value to be used
+			   with errors.Is. The entries are registered in init() so that errorx.Lookup can find them.
+*/
+func Generate(cat *errorx.Catalog, cfg GeneratorCfg) ([]byte, error) {
+	if cfg.PackageName == "" {
+		return nil, errorx.NewErrorX("package name is required")
+	}
+	err := cat.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = checkNames(cat)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by errorx-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %s\n\n", cfg.PackageName)
+	fmt.Fprintf(buf, "import (\n\"fmt\"\n\n%q\n)\n\n", errorxImport)
+
+	fmt.Fprintf(buf, "const (\n")
+	for _, entry := range cat.Errors {
+		name := typeName(entry.ID)
+		fmt.Fprintf(buf, "%sID = %q\n", name, entry.ID)
+		fmt.Fprintf(buf, "%sCode = %d\n", name, entry.Code)
+	}
+	fmt.Fprintf(buf, ")\n\n")
+
+	fmt.Fprintf(buf, "// sentinel values to be used with errors.Is, errors are matched by ID\n")
+	fmt.Fprintf(buf, "var (\n")
+	for _, entry := range cat.Errors {
+		name := typeName(entry.ID)
+		fmt.Fprintf(buf, "Err%s error = new%sError(errorx.NewErrorX(%sID), errorx.CaptureStack(0))\n", name, name, name)
+	}
+	fmt.Fprintf(buf, ")\n\n")
+
+	for _, entry := range cat.Errors {
+		generateEntry(buf, entry)
+	}
+
+	fmt.Fprintf(buf, "func init() {\n")
+	fmt.Fprintf(buf, "errorx.MustRegisterCatalog(&errorx.Catalog{\nErrors: []errorx.CatalogEntry{\n")
+	for _, entry := range cat.Errors {
+		fmt.Fprintf(buf, "{ID: %q, Code: %d, Status: %d, Message: %q, Severity: %q", entry.ID, entry.Code, entry.Status, entry.Message, entry.Severity)
+		if len(entry.Args) > 0 {
+			fmt.Fprintf(buf, ", Args: []errorx.CatalogArg{")
+			for _, arg := range entry.Args {
+				fmt.Fprintf(buf, "{Name: %q, Type: %q},", arg.Name, arg.Type)
+			}
+			fmt.Fprintf(buf, "}")
+		}
+		fmt.Fprintf(buf, "},\n")
+	}
+	fmt.Fprintf(buf, "},\n})\n}\n")
+
+	src := buf.Bytes()
+	formatted, err := format.Source(src)
+	if err != nil {
+		// return unformatted source as well so that the problem can be inspected
+		return src, errorx.NewErrorX("failed to format generated source : %v", err)
+	}
+	return formatted, nil
+}
+
+func generateEntry(buf *bytes.Buffer, entry errorx.CatalogEntry) {
+	name := typeName(entry.ID)
+
+	fmt.Fprintf(buf, "// %sError - %s\n", name, entry.Message)
+	fmt.Fprintf(buf, "type %sError struct {\n", name)
+	fmt.Fprintf(buf, "*errorx.ErrorX\n*errorx.ErrorWithCode\n*errorx.ErrorWithID\n*errorx.ErrorWithHttpStatus\n*errorx.ErrorWithSeverity\n*errorx.ErrorStacktrace\n*errorx.ErrorWithAttributes\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	params := make([]string, 0, len(entry.Args))
+	args := make([]string, 0, len(entry.Args))
+	for _, arg := range entry.Args {
+		argType := arg.Type
+		if argType == "" {
+			argType = "interface{}"
+		}
+		params = append(params, fmt.Sprintf("%s %s", arg.Name, argType))
+		args = append(args, arg.Name)
+	}
+	// stack is captured in the constructor and starts at its caller
+	fmt.Fprintf(buf, "func New%sError(%s) *%sError {\n", name, strings.Join(params, ", "), name)
+	if len(args) > 0 {
+		fmt.Fprintf(buf, "return new%sError(errorx.NewErrorX(%q, %s), errorx.CaptureStack(1))\n", name, entry.Message, strings.Join(args, ", "))
+	} else {
+		fmt.Fprintf(buf, "return new%sError(errorx.NewErrorX(%q), errorx.CaptureStack(1))\n", name, entry.Message)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "func new%sError(base *errorx.ErrorX, stack *errorx.ErrorStacktrace) *%sError {\n", name, name)
+	fmt.Fprintf(buf, "return &%sError{\n", name)
+	fmt.Fprintf(buf, "base,\n")
+	fmt.Fprintf(buf, "errorx.NewErrorWithCode(%sCode),\n", name)
+	fmt.Fprintf(buf, "errorx.NewErrorWithID(%sID),\n", name)
+	fmt.Fprintf(buf, "errorx.NewErrorWithHttpStatus(%d),\n", entry.Status)
+	fmt.Fprintf(buf, "errorx.NewErrorWithSeverity(%q),\n", entry.Severity)
+	fmt.Fprintf(buf, "stack,\n")
+	fmt.Fprintf(buf, "errorx.NewErrorWithAttributes(),\n")
+	fmt.Fprintf(buf, "}\n}\n\n")
+
+	fmt.Fprintf(buf, "func (err *%sError) Is(target error) bool {\n", name)
+	fmt.Fprintf(buf, "return errorx.Match(err, target)\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "func (err *%sError) Format(s fmt.State, verb rune) {\n", name)
+	fmt.Fprintf(buf, "errorx.FormatError(err, s, verb)\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "func (err *%sError) WithAttributes(attrs ...errorx.Attribute) *%sError {\n", name, name)
+	fmt.Fprintf(buf, "err.AddAttributes(attrs...)\n")
+	fmt.Fprintf(buf, "return err\n")
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "func (err *%sError) Wrap(cause error) *%sError {\n", name, name)
+	fmt.Fprintf(buf, "err.ErrorX.Wrap(cause)\n")
+	fmt.Fprintf(buf, "return err\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// reservedArgNames are the packages used by generated constructors, arguments must not shadow them.
+var reservedArgNames = map[string]bool{
+	"fmt":    true,
+	"errorx": true,
+}
+
+// checkNames rejects IDs which are converted into the same type name, e.g. "user_not_found" and "user.not_found",
+// and argument names which can not be used as parameter names of the generated constructor.
+func checkNames(cat *errorx.Catalog) error {
+	names := make(map[string]string)
+	for _, entry := range cat.Errors {
+		name := typeName(entry.ID)
+		if id, ok := names[name]; ok {
+			return errorx.NewErrorX("error IDs '%s' and '%s' generate the same type name '%s'", id, entry.ID, name)
+		}
+		names[name] = entry.ID
+
+		args := make(map[string]bool)
+		for _, arg := range entry.Args {
+			if token.Lookup(arg.Name).IsKeyword() || types.Universe.Lookup(arg.Name) != nil || reservedArgNames[arg.Name] {
+				return errorx.NewErrorX("argument name '%s' of '%s' is a reserved Go identifier", arg.Name, entry.ID)
+			}
+			if args[arg.Name] {
+				return errorx.NewErrorX("duplicate argument name '%s' of '%s'", arg.Name, entry.ID)
+			}
+			args[arg.Name] = true
+		}
+	}
+	return nil
+}
+
+// typeName converts error ID such as "user.not_found" into "UserNotFound".
+func typeName(id string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range id {
+		if r == '_' || r == '.' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name != "" && unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
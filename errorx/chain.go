@@ -0,0 +1,65 @@
+package errorx
+
+type Wrapper interface {
+	Unwrap() error
+}
+
+// Next returns the next error in the chain. Cause() is preferred and Unwrap() is used when there is no cause,
+// so that errors wrapped by errorx and by fmt.Errorf("%w") can be mixed in the same chain.
+func Next(err error) error {
+	errorCauser, ok := err.(Causer)
+	if ok {
+		cause := errorCauser.Cause()
+		if cause != nil {
+			return cause
+		}
+	}
+	wrapper, ok := err.(Wrapper)
+	if ok {
+		return wrapper.Unwrap()
+	}
+	return nil
+}
+
+// Walk calls fn for each error in the chain starting from err until fn returns false.
+func Walk(err error, fn func(error) bool) {
+	for err != nil {
+		if !fn(err) {
+			return
+		}
+		err = Next(err)
+	}
+}
+
+// Chain returns all the errors in the chain starting from err.
+func Chain(err error) []error {
+	var chain []error
+	Walk(err, func(e error) bool {
+		chain = append(chain, e)
+		return true
+	})
+	return chain
+}
+
+/*
+	Match - reports whether err and target are the same kind of error. Errors are matched by ErrorID when both of them
+			have ID, by ErrorCode otherwise. Types which embed both ErrorWithID and ErrorWithCode should define Is with it.
+	For example;
+		func (err *MyError) Is(target error) bool {
+			return errorx.Match(err, target)
+		}
+*/
+func Match(err error, target error) bool {
+	errWithID, ok := err.(ErrorID)
+	targetWithID, targetOk := target.(ErrorID)
+	if ok && targetOk && errWithID.ID() != "" && targetWithID.ID() != "" {
+		return errWithID.ID() == targetWithID.ID()
+	}
+
+	errWithCode, ok := err.(ErrorCode)
+	targetWithCode, targetOk := target.(ErrorCode)
+	if ok && targetOk && errWithCode.Code() != 0 && targetWithCode.Code() != 0 {
+		return errWithCode.Code() == targetWithCode.Code()
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+	"github.com/kyawmyintthein/orange-contrib/errorx/catalog"
+)
+
+/*
+	errorx-gen - generates typed errors from error catalog (JSON or YAML).
+	For example;
+		errorx-gen -catalog ./errors.yaml -package usererrors -output ./usererrors/errors_gen.go
+*/
+func main() {
+	catalogPath := flag.String("catalog", "", "path of error catalog (.json, .yaml or .yml)")
+	packageName := flag.String("package", "", "package name of generated code")
+	output := flag.String("output", "", "output file, generated code is written to stdout if empty")
+	flag.Parse()
+
+	if *catalogPath == "" || *packageName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cat, err := errorx.LoadCatalog(*catalogPath)
+	if err != nil {
+		exit(err)
+	}
+
+	src, err := catalog.Generate(cat, catalog.GeneratorCfg{
+		PackageName: *packageName,
+	})
+	if err != nil {
+		exit(err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+
+	err = ioutil.WriteFile(*output, src, 0644)
+	if err != nil {
+		exit(err)
+	}
+}
+
+func exit(err error) {
+	fmt.Fprintf(os.Stderr, "errorx-gen: %v\n", err)
+	os.Exit(1)
+}
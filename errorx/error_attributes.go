@@ -0,0 +1,119 @@
+package errorx
+
+/*
+	Attribute - is key value context of an error such as user ID or order ID. Sensitive attributes are written
+				into logs and traces only, they are never included in client facing responses.
+*/
+type Attribute struct {
+	Key       string
+	Value     interface{}
+	Sensitive bool
+}
+
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func SensitiveAttr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value, Sensitive: true}
+}
+
+type ErrorAttributes interface {
+	Attributes() []Attribute
+}
+
+type ErrorWithAttributes struct {
+	attrs []Attribute
+}
+
+func NewErrorWithAttributes(attrs ...Attribute) *ErrorWithAttributes {
+	return &ErrorWithAttributes{attrs: attrs}
+}
+
+func (err *ErrorWithAttributes) Attributes() []Attribute {
+	return err.attrs
+}
+
+// AddAttributes adds the attributes, the value of existing key is replaced.
+func (err *ErrorWithAttributes) AddAttributes(attrs ...Attribute) {
+	for _, attr := range attrs {
+		replaced := false
+		for i := range err.attrs {
+			if err.attrs[i].Key == attr.Key {
+				err.attrs[i] = attr
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			err.attrs = append(err.attrs, attr)
+		}
+	}
+}
+
+/*
+	WithAttributes - attaches attributes to the error. They are added to the error itself if it embeds ErrorWithAttributes,
+					 otherwise the error is wrapped. nil is returned if err is nil.
+*/
+func WithAttributes(err error, attrs ...Attribute) error {
+	if err == nil {
+		return nil
+	}
+	attributeAdder, ok := err.(interface{ AddAttributes(...Attribute) })
+	if ok {
+		attributeAdder.AddAttributes(attrs...)
+		return err
+	}
+	return &attributedError{
+		NewErrorWithAttributes(attrs...),
+		err,
+	}
+}
+
+// attributedError carries attributes of an error which does not support them, it is transparent otherwise.
+type attributedError struct {
+	*ErrorWithAttributes
+	err error
+}
+
+func (e *attributedError) Error() string { return e.err.Error() }
+
+func (e *attributedError) Cause() error { return e.err }
+
+func (e *attributedError) Unwrap() error { return e.err }
+
+// AllAttributes merges the attributes along the chain. When the same key is found, the outer error wins.
+func AllAttributes(err error) []Attribute {
+	var attrs []Attribute
+	seen := make(map[string]bool)
+	Walk(err, func(e error) bool {
+		errWithAttributes, ok := e.(ErrorAttributes)
+		if !ok {
+			return true
+		}
+		for _, attr := range errWithAttributes.Attributes() {
+			if seen[attr.Key] {
+				continue
+			}
+			seen[attr.Key] = true
+			attrs = append(attrs, attr)
+		}
+		return true
+	})
+	return attrs
+}
+
+// PublicAttributes returns the merged attributes which are not sensitive, e.g. to be included in responses.
+func PublicAttributes(err error) map[string]interface{} {
+	var public map[string]interface{}
+	for _, attr := range AllAttributes(err) {
+		if attr.Sensitive {
+			continue
+		}
+		if public == nil {
+			public = make(map[string]interface{})
+		}
+		public[attr.Key] = attr.Value
+	}
+	return public
+}
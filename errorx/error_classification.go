@@ -0,0 +1,154 @@
+package errorx
+
+import (
+	"context"
+	"io"
+	"syscall"
+	"time"
+)
+
+type Retryable interface {
+	Retryable() bool
+}
+
+type Temporary interface {
+	Temporary() bool
+}
+
+type Timeout interface {
+	Timeout() bool
+}
+
+type RetryAfterHint interface {
+	// RetryAfter returns how long to wait before retrying, zero if there is no hint.
+	RetryAfter() time.Duration
+}
+
+/*
+	ErrorWithRetry - is to declare whether the error is transient. Temporary and timeout errors are retryable
+					 unless Retryable says otherwise.
+*/
+type ErrorWithRetry struct {
+	retryable  bool
+	retryAfter time.Duration
+}
+
+func NewErrorWithRetry(retryable bool, retryAfter time.Duration) *ErrorWithRetry {
+	return &ErrorWithRetry{retryable: retryable, retryAfter: retryAfter}
+}
+
+func (err *ErrorWithRetry) Retryable() bool {
+	return err.retryable
+}
+
+func (err *ErrorWithRetry) RetryAfter() time.Duration {
+	return err.retryAfter
+}
+
+type ErrorWithTimeout struct{}
+
+func NewErrorWithTimeout() *ErrorWithTimeout {
+	return &ErrorWithTimeout{}
+}
+
+func (err *ErrorWithTimeout) Timeout() bool {
+	return true
+}
+
+func (err *ErrorWithTimeout) Temporary() bool {
+	return true
+}
+
+/*
+	IsRetryable - classifies the chain. The first error in the chain which implements Retryable, Timeout or
+				  Temporary decides, and connection reset, refused and unexpected EOF are retryable. A bare
+				  context.Canceled or context.DeadlineExceeded is not retryable, but a timeout which wraps them,
+				  e.g. http.Client timeout, is. Whether the caller gave up is decided from the caller's context.
+*/
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	retryable := false
+	Walk(err, func(e error) bool {
+		classified, ok := e.(Retryable)
+		if ok {
+			retryable = classified.Retryable()
+			return false
+		}
+		if e == context.Canceled || e == context.DeadlineExceeded {
+			return false
+		}
+		timeout, ok := e.(Timeout)
+		if ok && timeout.Timeout() {
+			retryable = true
+			return false
+		}
+		temporary, ok := e.(Temporary)
+		if ok && temporary.Temporary() {
+			retryable = true
+			return false
+		}
+		if isTransientNetworkError(e) {
+			retryable = true
+			return false
+		}
+		return true
+	})
+	return retryable
+}
+
+// IsTimeout reports whether any error in the chain is a timeout, including context deadline.
+func IsTimeout(err error) bool {
+	timeout := false
+	Walk(err, func(e error) bool {
+		t, ok := e.(Timeout)
+		if ok && t.Timeout() {
+			timeout = true
+			return false
+		}
+		if e == context.DeadlineExceeded {
+			timeout = true
+			return false
+		}
+		return true
+	})
+	return timeout
+}
+
+// IsTemporary reports whether the first error in the chain which implements Temporary is temporary.
+func IsTemporary(err error) bool {
+	temporary := false
+	Walk(err, func(e error) bool {
+		t, ok := e.(Temporary)
+		if ok {
+			temporary = t.Temporary()
+			return false
+		}
+		return true
+	})
+	return temporary
+}
+
+// RetryAfter returns the first retry-after hint in the chain.
+func RetryAfter(err error) (time.Duration, bool) {
+	var retryAfter time.Duration
+	Walk(err, func(e error) bool {
+		hint, ok := e.(RetryAfterHint)
+		if ok && hint.RetryAfter() > 0 {
+			retryAfter = hint.RetryAfter()
+			return false
+		}
+		return true
+	})
+	return retryAfter, retryAfter > 0
+}
+
+func isTransientNetworkError(err error) bool {
+	if err == io.ErrUnexpectedEOF {
+		return true
+	}
+	errno, ok := err.(syscall.Errno)
+	return ok && (errno == syscall.ECONNRESET || errno == syscall.ECONNREFUSED || errno == syscall.ECONNABORTED)
+}
@@ -15,3 +15,9 @@ func NewErrorWithCode(code int) *ErrorWithCode {
 func (err *ErrorWithCode) Code() int {
 	return err.code
 }
+
+// Is matches target by ErrorCode, so that errors.Is works for the errors which embed ErrorWithCode.
+func (err *ErrorWithCode) Is(target error) bool {
+	targetWithCode, ok := target.(ErrorCode)
+	return ok && err.code != 0 && targetWithCode.Code() == err.code
+}
@@ -15,3 +15,9 @@ func NewErrorWithID(id string) *ErrorWithID {
 func (err *ErrorWithID) ID() string {
 	return err.id
 }
+
+// Is matches target by ErrorID, so that errors.Is works for the errors which embed ErrorWithID.
+func (err *ErrorWithID) Is(target error) bool {
+	targetWithID, ok := target.(ErrorID)
+	return ok && err.id != "" && targetWithID.ID() == err.id
+}
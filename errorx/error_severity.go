@@ -0,0 +1,35 @@
+package errorx
+
+type Severity string
+
+const (
+	SeverityDebug    Severity = "debug"
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityError    Severity = "error"
+	SeverityCritical Severity = "critical"
+)
+
+type ErrorSeverity interface {
+	Severity() Severity
+}
+
+type ErrorWithSeverity struct {
+	severity Severity
+}
+
+func NewErrorWithSeverity(severity Severity) *ErrorWithSeverity {
+	return &ErrorWithSeverity{severity: severity}
+}
+
+func (err *ErrorWithSeverity) Severity() Severity {
+	return err.severity
+}
+
+func (s Severity) valid() bool {
+	switch s {
+	case SeverityDebug, SeverityInfo, SeverityWarning, SeverityError, SeverityCritical:
+		return true
+	}
+	return false
+}
@@ -2,10 +2,10 @@ package errorx
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"path"
 	"runtime"
+	"strings"
 	"sync"
 )
 
@@ -41,6 +41,9 @@ func NewErrorWithStackTrace(stackLen int, callerLen int) *ErrorStacktrace {
 }
 
 func (e *ErrorStacktrace) StackAddrs() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
 	buf := bytes.NewBuffer(make([]byte, 0, len(e.stack)*8))
 	for _, pc := range e.stack {
 		fmt.Fprintf(buf, "0x%x ", pc)
@@ -49,26 +52,39 @@ func (e *ErrorStacktrace) StackAddrs() string {
 	return string(bufBytes[:len(bufBytes)-1])
 }
 
+// StackFrames resolves the program counters into frames, inlined calls are expanded into their own frames.
 func (e *ErrorStacktrace) StackFrames() []StackFrame {
 	e.framesOnce.Do(func() {
-		e.stackFrames = make([]StackFrame, len(e.stack))
-		for i, pc := range e.stack {
-			frame := &e.stackFrames[i]
-			frame.PC = pc
-			frame.Func = runtime.FuncForPC(pc)
-			if frame.Func != nil {
-				frame.FuncName = frame.Func.Name()
-				frame.File, frame.LineNumber = frame.Func.FileLine(frame.PC - 1)
+		e.stackFrames = make([]StackFrame, 0, len(e.stack))
+		if len(e.stack) == 0 {
+			return
+		}
+		frames := runtime.CallersFrames(e.stack)
+		for {
+			frame, more := frames.Next()
+			e.stackFrames = append(e.stackFrames, StackFrame{
+				PC:         frame.PC,
+				Func:       frame.Func,
+				FuncName:   frame.Function,
+				File:       frame.File,
+				LineNumber: frame.Line,
+			})
+			if !more {
+				break
 			}
 		}
 	})
 	return e.stackFrames
 }
 
+// GetStack returns the frames which are accepted by the stack filter, see SetStackFilter.
 func (e *ErrorStacktrace) GetStack() string {
 	stackFrames := e.StackFrames()
 	buf := bytes.NewBuffer(make([]byte, 0, 256))
 	for _, frame := range stackFrames {
+		if !acceptFrame(frame) {
+			continue
+		}
 		_, _ = buf.WriteString(frame.FuncName)
 		_, _ = buf.WriteString("\n")
 		fmt.Fprintf(buf, "\t%s:%d +0x%x\n",
@@ -79,22 +95,74 @@ func (e *ErrorStacktrace) GetStack() string {
 
 func (e *ErrorStacktrace) GetStackAsJSON() interface{} {
 	stackFrames := e.StackFrames()
-	buf := bytes.NewBuffer(make([]byte, 0, 256))
-	var (
-		data []byte
-		i    interface{}
-	)
-	data = append(data, '[')
-	for i, frame := range stackFrames {
-		if i != 0 {
-			data = append(data, ',')
+	frames := make([]interface{}, 0, len(stackFrames))
+	for _, frame := range stackFrames {
+		if !acceptFrame(frame) {
+			continue
 		}
-		name := path.Base(frame.FuncName)
-		frameBytes := []byte(fmt.Sprintf(`{"filepath": "%s", "name": "%s", "line": %d}`, frame.File, name, frame.LineNumber))
-		data = append(data, frameBytes...)
+		// values are kept in map instead of formatted JSON text, so that they are escaped by the JSON encoder
+		frames = append(frames, map[string]interface{}{
+			"filepath": frame.File,
+			"name":     path.Base(frame.FuncName),
+			"line":     frame.LineNumber,
+		})
 	}
-	data = append(data, ']')
-	buf.Write(data)
-	_ = json.Unmarshal(data, &i)
-	return i
+	return frames
+}
+
+var (
+	stackMu     sync.RWMutex
+	stackDepth  = 32
+	stackFilter = DefaultStackFilter
+)
+
+// SetStackDepth sets the maximum number of frames captured by Wrap, Wrapf and CaptureStack.
+func SetStackDepth(depth int) {
+	stackMu.Lock()
+	defer stackMu.Unlock()
+	if depth > 0 {
+		stackDepth = depth
+	}
+}
+
+func StackDepth() int {
+	stackMu.RLock()
+	defer stackMu.RUnlock()
+	return stackDepth
+}
+
+// SetStackFilter sets the filter of frames printed by GetStack and GetStackAsJSON, nil keeps all the frames.
+func SetStackFilter(filter func(StackFrame) bool) {
+	stackMu.Lock()
+	defer stackMu.Unlock()
+	stackFilter = filter
+}
+
+// DefaultStackFilter drops the frames of Go runtime and vendored packages.
+func DefaultStackFilter(frame StackFrame) bool {
+	if strings.HasPrefix(frame.FuncName, "runtime.") || strings.HasPrefix(frame.FuncName, "testing.") {
+		return false
+	}
+	return !strings.Contains(frame.File, "/vendor/")
+}
+
+func acceptFrame(frame StackFrame) bool {
+	stackMu.RLock()
+	filter := stackFilter
+	stackMu.RUnlock()
+	return filter == nil || filter(frame)
+}
+
+// newStacktraceFromFrames restores the stack trace of a deserialized error, it has frames but no program counters.
+func newStacktraceFromFrames(frames []StackFrame) *ErrorStacktrace {
+	e := &ErrorStacktrace{}
+	e.framesOnce.Do(func() {
+		e.stackFrames = frames
+	})
+	return e
+}
+
+// CaptureStack captures the stack with the configured depth. skip 0 starts the stack at the caller of CaptureStack.
+func CaptureStack(skip int) *ErrorStacktrace {
+	return NewErrorWithStackTrace(StackDepth(), skip+3)
 }
@@ -0,0 +1,120 @@
+package errorx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Translator is satisfied by localex.Translator, translation arguments are given as name and value pairs.
+type Translator interface {
+	Translate(ctx context.Context, messageID string, argKvs ...string) string
+}
+
+type ErrorTranslation interface {
+	TranslationKey() string
+	TranslationArgs() map[string]interface{}
+}
+
+/*
+	ErrorWithTranslation - declares translation key and named arguments of user-facing message. Arguments are referred
+						   as {{var_name}} in the translation. For example;
+							"user_not_found": "User {{var_user_id}} is not found."
+*/
+type ErrorWithTranslation struct {
+	key  string
+	args map[string]interface{}
+}
+
+func NewErrorWithTranslation(key string, args map[string]interface{}) *ErrorWithTranslation {
+	return &ErrorWithTranslation{key: key, args: args}
+}
+
+func (err *ErrorWithTranslation) TranslationKey() string {
+	return err.key
+}
+
+func (err *ErrorWithTranslation) TranslationArgs() map[string]interface{} {
+	return err.args
+}
+
+/*
+	LocalizedMessage - renders user-facing message of the error with the locale of ctx. Translation key is taken from
+					   the first error in the chain with ErrorTranslation, ErrorID is used otherwise. Arguments of an
+					   error without named arguments are named by its catalog entry, or by position, e.g. {{var_0}}.
+					   Formatted message of err is returned when there is no translation.
+*/
+func LocalizedMessage(ctx context.Context, translator Translator, err error) string {
+	if err == nil {
+		return ""
+	}
+	fallback := err.Error()
+	errorFormatter, ok := err.(ErrorFormatter)
+	if ok {
+		fallback = errorFormatter.FormattedMessage()
+	}
+	if translator == nil {
+		return fallback
+	}
+
+	key, args := translation(err)
+	if key == "" {
+		return fallback
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	argKvs := make([]string, 0, len(args)*2)
+	for _, name := range names {
+		argKvs = append(argKvs, name, fmt.Sprint(args[name]))
+	}
+
+	translated := translator.Translate(ctx, key, argKvs...)
+	if translated == "" || translated == key {
+		return fallback
+	}
+	return translated
+}
+
+func translation(err error) (string, map[string]interface{}) {
+	var (
+		key  string
+		args map[string]interface{}
+	)
+	Walk(err, func(e error) bool {
+		errTranslation, ok := e.(ErrorTranslation)
+		if ok && errTranslation.TranslationKey() != "" {
+			key = errTranslation.TranslationKey()
+			args = errTranslation.TranslationArgs()
+			return false
+		}
+		errWithID, ok := e.(ErrorID)
+		if ok && errWithID.ID() != "" {
+			key = errWithID.ID()
+			args = positionalArgs(e, key)
+			return false
+		}
+		return true
+	})
+	return key, args
+}
+
+func positionalArgs(err error, id string) map[string]interface{} {
+	errorFormatter, ok := err.(ErrorFormatter)
+	if !ok || len(errorFormatter.GetArgs()) == 0 {
+		return nil
+	}
+	entry, _ := Lookup(id)
+	args := make(map[string]interface{}, len(errorFormatter.GetArgs()))
+	for i, arg := range errorFormatter.GetArgs() {
+		if i < len(entry.Args) {
+			args[entry.Args[i].Name] = arg
+		}
+		args[strconv.Itoa(i)] = arg
+	}
+	return args
+}
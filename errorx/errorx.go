@@ -51,6 +51,8 @@ func (e *ErrorX) Error() string {
 
 func (w *ErrorX) Cause() error { return w.cause }
 
+func (w *ErrorX) Unwrap() error { return w.cause }
+
 func GetErrorMessages(e error) string {
 	return extractFullErrorMessage(e, false)
 }
@@ -60,40 +62,33 @@ func GetErrorMessagesWithStack(e error) string {
 }
 
 func extractFullErrorMessage(e error, includeStack bool) string {
-	type causer interface {
-		Cause() error
-	}
-
-	var ok bool
 	var lastClErr error
 	errMsg := bytes.NewBuffer(make([]byte, 0, 1024))
-	dbxErr := e
-	for {
-		_, ok := dbxErr.(StackTracer)
+	complete := false
+	Walk(e, func(err error) bool {
+		_, ok := err.(StackTracer)
 		if ok {
-			lastClErr = dbxErr
+			lastClErr = err
+		}
+		if complete {
+			return true
 		}
 
-		errorWithFormat, ok := dbxErr.(ErrorFormatter)
+		if errMsg.Len() > 0 {
+			errMsg.WriteString(", ")
+		}
+		errorWithFormat, ok := err.(ErrorFormatter)
 		if ok {
 			errMsg.WriteString(errorWithFormat.FormattedMessage())
+			return true
 		}
 
-		errorCauser, ok := dbxErr.(causer)
-		if ok {
-			innerErr := errorCauser.Cause()
-			if innerErr == nil {
-				break
-			}
-			dbxErr = innerErr
-		} else {
-			// We have reached the end and traveresed all inner errors.
-			// Add last message and exit loop.
-			errMsg.WriteString(dbxErr.Error())
-			break
-		}
-		errMsg.WriteString(", ")
-	}
+		// message of other errors, e.g. fmt.Errorf("%w"), already contains the messages of wrapped errors.
+		// The rest of the chain is still walked to find the stack trace.
+		errMsg.WriteString(err.Error())
+		complete = true
+		return true
+	})
 
 	stackError, ok := lastClErr.(StackTracer)
 	if includeStack && ok {
@@ -103,17 +98,14 @@ func extractFullErrorMessage(e error, includeStack bool) string {
 	return errMsg.String()
 }
 
+// Cause returns the root cause of the error by walking both Cause() and Unwrap() chain.
 func Cause(err error) error {
-	type causer interface {
-		Cause() error
-	}
-
 	for err != nil {
-		cause, ok := err.(causer)
-		if !ok {
+		next := Next(err)
+		if next == nil {
 			break
 		}
-		err = cause.Cause()
+		err = next
 	}
 	return err
 }
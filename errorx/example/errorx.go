@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/kyawmyintthein/orange-contrib/errorx"
@@ -25,6 +26,16 @@ func NewMyError() *MyError {
 	}
 }
 
+// Is is required because both ErrorWithCode and ErrorWithID provide Is
+func (err *MyError) Is(target error) bool {
+	return errorx.Match(err, target)
+}
+
+// Format prints the messages of the chain, and the stack traces as well with %+v
+func (err *MyError) Format(s fmt.State, verb rune) {
+	errorx.FormatError(err, s, verb)
+}
+
 func main() {
 	err := getMyError()
 	fmt.Println("Error")
@@ -44,6 +55,20 @@ func main() {
 		fmt.Println("---------------------------------------------------------------------------------------------")
 	}
 
+	wrappedErr := fmt.Errorf("get my error : %w", err)
+	if errors.Is(wrappedErr, NewMyError()) {
+		fmt.Println("errors.Is")
+		fmt.Println("wrapped error is matched by error ID")
+		fmt.Println("---------------------------------------------------------------------------------------------")
+	}
+
+	var myErr *MyError
+	if errors.As(wrappedErr, &myErr) {
+		fmt.Println("errors.As")
+		fmt.Println(myErr.ID())
+		fmt.Println("---------------------------------------------------------------------------------------------")
+	}
+
 	errWithCode, ok := err.(errorx.ErrorCode)
 	if ok {
 		fmt.Println("Error Code")
@@ -65,6 +90,10 @@ func main() {
 		fmt.Println("---------------------------------------------------------------------------------------------")
 	}
 
+	fmt.Println("Wrap")
+	fmt.Printf("%+v\n", errorx.Wrapf(err, "load file : %s", "/tmp/dat"))
+	fmt.Println("---------------------------------------------------------------------------------------------")
+
 	errStacktrace, ok := err.(errorx.StackTracer)
 	if ok {
 		fmt.Println("Stacktrace")
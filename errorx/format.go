@@ -0,0 +1,53 @@
+package errorx
+
+import (
+	"fmt"
+	"io"
+)
+
+/*
+	FormatError - implements fmt.Formatter for errorx errors. %s and %v print the messages of the chain,
+				  %+v prints the stack trace of each error in the chain as well, %q prints quoted messages.
+				  Error types which embed ErrorX can use it to support %+v. For example;
+					func (err *MyError) Format(s fmt.State, verb rune) {
+						errorx.FormatError(err, s, verb)
+					}
+*/
+func FormatError(err error, s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			writeChainWithStack(s, err)
+			return
+		}
+		io.WriteString(s, GetErrorMessages(err))
+	case 's':
+		io.WriteString(s, GetErrorMessages(err))
+	case 'q':
+		fmt.Fprintf(s, "%q", GetErrorMessages(err))
+	}
+}
+
+func writeChainWithStack(w io.Writer, err error) {
+	first := true
+	Walk(err, func(e error) bool {
+		if !first {
+			io.WriteString(w, "\ncaused by: ")
+		}
+		first = false
+
+		errorWithFormat, ok := e.(ErrorFormatter)
+		if ok {
+			io.WriteString(w, errorWithFormat.FormattedMessage())
+		} else {
+			io.WriteString(w, e.Error())
+		}
+
+		errStacktrace, ok := e.(StackTracer)
+		if ok {
+			io.WriteString(w, "\n")
+			io.WriteString(w, errStacktrace.GetStack())
+		}
+		return true
+	})
+}
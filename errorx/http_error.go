@@ -1,7 +1,5 @@
 package errorx
 
-import "strconv"
-
 const (
 	DefaultHttpStatusCode int = 500 // Internal Server Error
 )
@@ -22,16 +20,12 @@ func (err *ErrorWithHttpStatus) StatusCode() int {
 	return err.httpStatus
 }
 
+// GenerateHttpStatusCodeFromErrorCode takes the first three digits of the code as http status. Use HttpStatusFromCode
+// to map with the configured StatusMapping.
 func GenerateHttpStatusCodeFromErrorCode(code int) int {
-	arr := strconv.Itoa(code)
-	if len(arr) >= 0 {
-		f3d := arr[:3]
-		statusCode, err := strconv.Atoi(f3d)
-		if err != nil {
-			return DefaultHttpStatusCode
-		}
-		return statusCode
+	status, ok := PrefixMapping{}.StatusCode(code)
+	if !ok {
+		return DefaultHttpStatusCode
 	}
-	return DefaultHttpStatusCode
-
+	return status
 }
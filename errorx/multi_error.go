@@ -0,0 +1,205 @@
+package errorx
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+type MultiError interface {
+	Errors() []error
+}
+
+/*
+	GetFieldErrors - returns the errors of the first MultiError in the chain which has errors, e.g. a validation
+					 error wrapped by another error. Empty lists are skipped because every DecodedError node
+					 implements MultiError.
+*/
+func GetFieldErrors(err error) []error {
+	var errs []error
+	Walk(err, func(e error) bool {
+		multiError, ok := e.(MultiError)
+		if !ok {
+			return true
+		}
+		errs = multiError.Errors()
+		return len(errs) == 0
+	})
+	return errs
+}
+
+type ErrorField interface {
+	Field() string
+}
+
+/*
+	FieldError - is an error of a single field, e.g. a validation error of request body.
+				 Field is the path of the field built by FieldPath. For example;
+					errorx.NewFieldError(errorx.FieldPath("items", 2, "price"), 400101, "price must be positive")
+*/
+type FieldError struct {
+	*ErrorX
+	*ErrorWithCode
+	field string
+}
+
+func NewFieldError(field string, code int, messageFormat string, args ...interface{}) *FieldError {
+	return &FieldError{
+		NewErrorX(messageFormat, args...),
+		NewErrorWithCode(code),
+		field,
+	}
+}
+
+func (err *FieldError) Field() string {
+	return err.field
+}
+
+// StatusCode is mapped from the code, field error is 400 Bad Request if the code is not mapped to 4xx or 5xx.
+func (err *FieldError) StatusCode() int {
+	status, ok := GetStatusMapping().StatusCode(err.Code())
+	if !ok || status < http.StatusBadRequest || status > 599 {
+		return http.StatusBadRequest
+	}
+	return status
+}
+
+func (err *FieldError) Error() string {
+	if err.field == "" {
+		return err.ErrorX.Error()
+	}
+	return err.field + ": " + err.ErrorX.Error()
+}
+
+// FieldPath joins field names and indexes into a path, e.g. FieldPath("items", 2, "price") is "items[2].price".
+func FieldPath(parts ...interface{}) string {
+	buf := bytes.NewBuffer(make([]byte, 0, 32))
+	for _, part := range parts {
+		switch p := part.(type) {
+		case int:
+			buf.WriteString("[" + strconv.Itoa(p) + "]")
+		case string:
+			if buf.Len() > 0 {
+				buf.WriteString(".")
+			}
+			buf.WriteString(p)
+		}
+	}
+	return buf.String()
+}
+
+/*
+	ErrorList - collects several errors and returns them as one error. Http status is generated from the code,
+				or it is the highest status of the collected errors if the code is not set.
+	For example;
+		errs := errorx.NewErrorList(400100, "invalid order request")
+		errs.AddField(errorx.FieldPath("items", 2, "price"), 400101, "price must be positive")
+		return errs.ErrorOrNil()
+*/
+type ErrorList struct {
+	*ErrorX
+	code   int
+	errors []error
+}
+
+func NewErrorList(code int, messageFormat string, args ...interface{}) *ErrorList {
+	return &ErrorList{
+		ErrorX: NewErrorX(messageFormat, args...),
+		code:   code,
+	}
+}
+
+// Add appends the error, nil is ignored and the errors of another ErrorList are flattened.
+func (list *ErrorList) Add(err error) *ErrorList {
+	if err == nil {
+		return list
+	}
+	other, ok := err.(*ErrorList)
+	if ok {
+		list.errors = append(list.errors, other.errors...)
+		return list
+	}
+	list.errors = append(list.errors, err)
+	return list
+}
+
+func (list *ErrorList) AddField(field string, code int, messageFormat string, args ...interface{}) *ErrorList {
+	return list.Add(NewFieldError(field, code, messageFormat, args...))
+}
+
+func (list *ErrorList) Errors() []error {
+	return list.errors
+}
+
+func (list *ErrorList) Len() int {
+	return len(list.errors)
+}
+
+// ErrorOrNil returns nil when no error is collected, it avoids returning typed nil as error.
+func (list *ErrorList) ErrorOrNil() error {
+	if list == nil || len(list.errors) == 0 {
+		return nil
+	}
+	return list
+}
+
+// Is reports whether any of the collected errors matches target, so that errors.Is checks each of them.
+// The cause of the list is still checked by errors.Is through Unwrap of ErrorX.
+func (list *ErrorList) Is(target error) bool {
+	for _, err := range list.errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first collected error which matches target, so that errors.As checks each of them.
+func (list *ErrorList) As(target interface{}) bool {
+	for _, err := range list.errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (list *ErrorList) Code() int {
+	return list.code
+}
+
+func (list *ErrorList) StatusCode() int {
+	if list.code != 0 {
+		return HttpStatusFromCode(list.code)
+	}
+
+	status := 0
+	for _, err := range list.errors {
+		errStatus := DefaultHttpStatusCode
+		httpError, ok := err.(HttpError)
+		if ok && httpError.StatusCode() != 0 {
+			errStatus = httpError.StatusCode()
+		}
+		if errStatus > status {
+			status = errStatus
+		}
+	}
+	if status == 0 {
+		return http.StatusBadRequest
+	}
+	return status
+}
+
+func (list *ErrorList) Error() string {
+	buf := bytes.NewBufferString(list.ErrorX.Error())
+	for i, err := range list.errors {
+		if i == 0 {
+			buf.WriteString(": ")
+		} else {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(err.Error())
+	}
+	return buf.String()
+}
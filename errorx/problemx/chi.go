@@ -0,0 +1,31 @@
+package problemx
+
+import (
+	"net/http"
+)
+
+// Recoverer renders panic of the handler as 500 problem.
+func (rd *renderer) Recoverer(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			rd.Render(w, r, NewPanicError(recovered))
+		}()
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+func (rd *renderer) NotFound(w http.ResponseWriter, r *http.Request) {
+	rd.Render(w, r, NewRouteNotFoundError(r.Method, r.URL.Path))
+}
+
+func (rd *renderer) MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	rd.Render(w, r, NewMethodNotAllowedError(r.Method, r.URL.Path))
+}
@@ -0,0 +1,6 @@
+package problemx
+
+type ProblemCfg struct {
+	Production  bool   `json:"production" mapstructure:"production"`       // causes and stack trace are hidden in production mode
+	TypeBaseURL string `json:"type_base_url" mapstructure:"type_base_url"` // type of problem is TypeBaseURL + error ID, "about:blank" if empty
+}
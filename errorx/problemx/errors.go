@@ -0,0 +1,45 @@
+package problemx
+
+import (
+	"net/http"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+)
+
+type RouteNotFoundError struct {
+	*errorx.ErrorX
+	*errorx.ErrorWithHttpStatus
+}
+
+func NewRouteNotFoundError(method string, path string) *RouteNotFoundError {
+	return &RouteNotFoundError{
+		errorx.NewErrorX("route %s %s is not found", method, path),
+		errorx.NewErrorWithHttpStatus(http.StatusNotFound),
+	}
+}
+
+type MethodNotAllowedError struct {
+	*errorx.ErrorX
+	*errorx.ErrorWithHttpStatus
+}
+
+func NewMethodNotAllowedError(method string, path string) *MethodNotAllowedError {
+	return &MethodNotAllowedError{
+		errorx.NewErrorX("method %s is not allowed for %s", method, path),
+		errorx.NewErrorWithHttpStatus(http.StatusMethodNotAllowed),
+	}
+}
+
+type PanicError struct {
+	*errorx.ErrorX
+	*errorx.ErrorWithHttpStatus
+	*errorx.ErrorStacktrace
+}
+
+func NewPanicError(recovered interface{}) *PanicError {
+	return &PanicError{
+		errorx.NewErrorX("panic : %v", recovered),
+		errorx.NewErrorWithHttpStatus(http.StatusInternalServerError),
+		errorx.NewErrorWithStackTrace(32, 4),
+	}
+}
@@ -0,0 +1,33 @@
+package problemx
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// GinHandlerFunc is gin handler which returns error, the error is rendered as problem by Renderer.GinHandler.
+type GinHandlerFunc func(*gin.Context) error
+
+func (rd *renderer) GinHandler(fn GinHandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := fn(c)
+		if err != nil {
+			rd.GinRender(c, err)
+		}
+	}
+}
+
+func (rd *renderer) GinRender(c *gin.Context, err error) {
+	rd.Render(c.Writer, c.Request, err)
+	c.Abort()
+}
+
+// GinErrors renders the last error added by c.Error() if the response is not written by the handlers.
+func (rd *renderer) GinErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		rd.Render(c.Writer, c.Request, c.Errors.Last().Err)
+	}
+}
@@ -0,0 +1,23 @@
+package problemx
+
+import (
+	"context"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+	"github.com/kyawmyintthein/orange-contrib/optionx"
+)
+
+/*
+	WithTranslator - renders detail of the problem in the request locale by errorx.LocalizedMessage,
+					 e.g. problemx.WithTranslator(localex.NewTranslator(cfg, storage)).
+*/
+type translatorKey struct{}
+
+func WithTranslator(translator errorx.Translator) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, translatorKey{}, translator)
+	}
+}
@@ -0,0 +1,277 @@
+package problemx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi/middleware"
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+	"github.com/kyawmyintthein/orange-contrib/logx"
+	"github.com/kyawmyintthein/orange-contrib/middlewarex"
+	"github.com/kyawmyintthein/orange-contrib/optionx"
+	"github.com/kyawmyintthein/orange-contrib/tracingx/jaegerx"
+	"github.com/kyawmyintthein/orange-contrib/tracingx/newrelicx"
+	newrelic "github.com/newrelic/go-agent"
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
+)
+
+const (
+	ContentType string = "application/problem+json"
+
+	blankType            string = "about:blank"
+	requestIDHeader      string = "X-Request-Id"
+	internalErrorMessage string = "internal server error"
+)
+
+/*
+Problem - is the problem details of RFC 7807. Code, ErrorID, RequestID, TraceID, Causes and Stacktrace are extension members.
+For example;
+
+	{
+		"type": "https://errors.example.com/user_not_found",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "user not found, user ID : 7",
+		"instance": "/users/7",
+		"code": 404001,
+		"error_id": "user_not_found",
+		"request_id": "host/abc-000001",
+		"trace_id": "5b8aa5a2d2c872e8"
+	}
+*/
+type Problem struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Code       int                    `json:"code,omitempty"`
+	ErrorID    string                 `json:"error_id,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	TraceID    string                 `json:"trace_id,omitempty"`
+	Errors     []FieldProblem         `json:"errors,omitempty"`     // errors of errorx.MultiError, e.g. validation errors
+	Attributes map[string]interface{} `json:"attributes,omitempty"` // sensitive attributes are not included
+	Causes     []string               `json:"causes,omitempty"`
+	Stacktrace interface{}            `json:"stacktrace,omitempty"`
+}
+
+type FieldProblem struct {
+	Field   string `json:"field,omitempty"`
+	Code    int    `json:"code,omitempty"`
+	ErrorID string `json:"error_id,omitempty"`
+	Detail  string `json:"detail"`
+}
+
+// HandlerFunc is http handler which returns error, the error is rendered as problem by Renderer.Handler.
+type HandlerFunc func(http.ResponseWriter, *http.Request) error
+
+type Renderer interface {
+	Problem(*http.Request, error) *Problem
+	Render(http.ResponseWriter, *http.Request, error)
+	Handler(HandlerFunc) http.HandlerFunc
+
+	// chi (and net/http) helpers, e.g. router.NotFound(renderer.NotFound)
+	Recoverer(http.Handler) http.Handler
+	NotFound(http.ResponseWriter, *http.Request)
+	MethodNotAllowed(http.ResponseWriter, *http.Request)
+
+	GinHandler(GinHandlerFunc) gin.HandlerFunc
+	GinRender(*gin.Context, error)
+	GinErrors() gin.HandlerFunc
+}
+
+type renderer struct {
+	config     *ProblemCfg
+	translator errorx.Translator
+}
+
+func NewRenderer(cfg *ProblemCfg, opts ...optionx.Option) Renderer {
+	options := optionx.NewOptions(opts...)
+	rd := &renderer{
+		config: cfg,
+	}
+
+	translator, ok := options.Context.Value(translatorKey{}).(errorx.Translator)
+	if translator != nil && ok {
+		rd.translator = translator
+	}
+	return rd
+}
+
+func (rd *renderer) Problem(r *http.Request, err error) *Problem {
+	ctx := context.Background()
+	if r != nil {
+		ctx = r.Context()
+	}
+	status := errorx.DefaultHttpStatusCode
+	statusFound := false
+	problem := &Problem{
+		Type: blankType,
+	}
+	var errorFormatter errorx.ErrorFormatter
+	var codeErr, idErr error
+
+	// values are taken from the first error in the chain which has them
+	errorx.Walk(err, func(e error) bool {
+		httpError, ok := e.(errorx.HttpError)
+		if ok && !statusFound && httpError.StatusCode() != 0 {
+			status = httpError.StatusCode()
+			statusFound = true
+		}
+		errWithCode, ok := e.(errorx.ErrorCode)
+		if ok && problem.Code == 0 {
+			problem.Code = errWithCode.Code()
+			codeErr = e
+		}
+		errWithID, ok := e.(errorx.ErrorID)
+		if ok && problem.ErrorID == "" {
+			problem.ErrorID = errWithID.ID()
+			idErr = e
+		}
+		formatter, ok := e.(errorx.ErrorFormatter)
+		if ok && errorFormatter == nil {
+			errorFormatter = formatter
+		}
+		return true
+	})
+	problem.Status = status
+	problem.Title = http.StatusText(status)
+	if problem.ErrorID != "" && rd.config.TypeBaseURL != "" {
+		problem.Type = rd.config.TypeBaseURL + problem.ErrorID
+	}
+
+	// detail is the message of the error which declares the ID or the code, annotations of the wrappers are internal
+	detailErr := idErr
+	if detailErr == nil {
+		detailErr = codeErr
+	}
+	if detailErr != nil {
+		errorFormatter, _ = detailErr.(errorx.ErrorFormatter)
+	} else {
+		detailErr = err
+	}
+
+	switch {
+	case rd.config.Production && status >= http.StatusInternalServerError && problem.ErrorID == "":
+		// message of an error which is not declared with ID may contain internal details
+		problem.Detail = internalErrorMessage
+	case rd.translator != nil:
+		problem.Detail = errorx.LocalizedMessage(ctx, rd.translator, detailErr)
+	case errorFormatter != nil:
+		problem.Detail = errorFormatter.FormattedMessage()
+	default:
+		problem.Detail = detailErr.Error()
+	}
+
+	problem.Attributes = errorx.PublicAttributes(err)
+
+	for _, item := range errorx.GetFieldErrors(err) {
+		problem.Errors = append(problem.Errors, rd.fieldProblem(ctx, item))
+	}
+
+	if r != nil {
+		problem.Instance = r.URL.Path
+		problem.RequestID = requestID(r)
+		problem.TraceID = traceID(r)
+	}
+
+	if !rd.config.Production {
+		for _, cause := range errorx.Chain(err)[1:] {
+			problem.Causes = append(problem.Causes, cause.Error())
+		}
+		errorx.Walk(err, func(e error) bool {
+			errStacktrace, ok := e.(errorx.StackTracer)
+			if ok {
+				problem.Stacktrace = errStacktrace.GetStackAsJSON()
+				return false
+			}
+			return true
+		})
+	}
+	return problem
+}
+
+func (rd *renderer) fieldProblem(ctx context.Context, err error) FieldProblem {
+	fieldProblem := FieldProblem{
+		Detail: err.Error(),
+	}
+	errorFormatter, ok := err.(errorx.ErrorFormatter)
+	if ok {
+		fieldProblem.Detail = errorFormatter.FormattedMessage()
+	}
+	if rd.translator != nil {
+		fieldProblem.Detail = errorx.LocalizedMessage(ctx, rd.translator, err)
+	}
+	errorField, ok := err.(errorx.ErrorField)
+	if ok {
+		fieldProblem.Field = errorField.Field()
+	}
+	errWithCode, ok := err.(errorx.ErrorCode)
+	if ok {
+		fieldProblem.Code = errWithCode.Code()
+	}
+	errWithID, ok := err.(errorx.ErrorID)
+	if ok {
+		fieldProblem.ErrorID = errWithID.ID()
+	}
+	return fieldProblem
+}
+
+func (rd *renderer) Render(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := context.Background()
+	var method, path string
+	if r != nil {
+		ctx = r.Context()
+		method, path = r.Method, r.URL.Path
+	}
+
+	problem := rd.Problem(r, err)
+	if problem.Status >= http.StatusInternalServerError {
+		logx.ErrorKVf(ctx, err, logx.KV{"RequestID": problem.RequestID, "TraceID": problem.TraceID}, "[%s] %s %s", PackageName, method, path)
+		jaegerx.SetErrorTags(opentracing.SpanFromContext(ctx), err)
+		newrelicx.NoticeError(newrelic.FromContext(ctx), err)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(problem.Status)
+	err = json.NewEncoder(w).Encode(problem)
+	if err != nil {
+		logx.Errorf(ctx, err, "[%s] failed to write problem response", PackageName)
+	}
+}
+
+func (rd *renderer) Handler(fn HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := fn(w, r)
+		if err != nil {
+			rd.Render(w, r, err)
+		}
+	}
+}
+
+func requestID(r *http.Request) string {
+	id := middlewarex.GetReqID(r.Context())
+	if id == "" {
+		id = middleware.GetReqID(r.Context())
+	}
+	if id == "" {
+		id = r.Header.Get(requestIDHeader)
+	}
+	return strings.TrimSpace(id)
+}
+
+func traceID(r *http.Request) string {
+	span := opentracing.SpanFromContext(r.Context())
+	if span == nil {
+		return ""
+	}
+	spanContext, ok := span.Context().(jaeger.SpanContext)
+	if !ok {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
@@ -0,0 +1,5 @@
+package problemx
+
+const (
+	PackageName = "ProblemX"
+)
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+	"github.com/kyawmyintthein/orange-contrib/errorx/reportx"
+)
+
+const (
+	publicKey string = "public"
+	projectID string = "42"
+)
+
+type envelope struct {
+	path        string
+	contentType string
+	auth        string
+	body        []byte
+}
+
+// checks the envelope sent by the Sentry sink against a local server, run with: go run ./errorx/reportx/_example/sentry
+func main() {
+	received := make(chan envelope, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- envelope{
+			path:        r.URL.Path,
+			contentType: r.Header.Get("Content-Type"),
+			auth:        r.Header.Get("X-Sentry-Auth"),
+			body:        body,
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://"+publicKey+"@", 1) + "/" + projectID
+	sink, err := reportx.NewSentrySink(&reportx.SentrySinkCfg{DSN: dsn})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reporter := reportx.NewReporter(&reportx.ReporterCfg{Window: time.Hour, Environment: "example"}, reportx.WithSink(sink))
+	reporter.Report(context.Background(), errorx.NewErrorX("payment declined for order %d", 7))
+	err = reporter.Flush(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	select {
+	case env := <-received:
+		err = checkEnvelope(env, dsn)
+	case <-time.After(5 * time.Second):
+		err = fmt.Errorf("no envelope received")
+	}
+	if err != nil {
+		log.Fatalf("invalid envelope : %v", err)
+	}
+	fmt.Println("envelope is valid")
+}
+
+func checkEnvelope(env envelope, dsn string) error {
+	if env.path != "/api/"+projectID+"/envelope/" {
+		return fmt.Errorf("unexpected path '%s'", env.path)
+	}
+	if env.contentType != "application/x-sentry-envelope" {
+		return fmt.Errorf("unexpected content type '%s'", env.contentType)
+	}
+	if !strings.Contains(env.auth, "sentry_key="+publicKey) || !strings.Contains(env.auth, "sentry_version=7") {
+		return fmt.Errorf("unexpected auth header '%s'", env.auth)
+	}
+
+	// envelope header, item header and event payload are separated by new lines
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(env.body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	if len(lines) != 3 {
+		return fmt.Errorf("expected 3 lines, got %d", len(lines))
+	}
+
+	var header struct {
+		EventID string `json:"event_id"`
+		DSN     string `json:"dsn"`
+		SentAt  string `json:"sent_at"`
+	}
+	err := json.Unmarshal(lines[0], &header)
+	if err != nil {
+		return fmt.Errorf("envelope header : %v", err)
+	}
+	if header.DSN != dsn || header.SentAt == "" {
+		return fmt.Errorf("unexpected envelope header %s", lines[0])
+	}
+
+	var item struct {
+		Type        string `json:"type"`
+		Length      int    `json:"length"`
+		ContentType string `json:"content_type"`
+	}
+	err = json.Unmarshal(lines[1], &item)
+	if err != nil {
+		return fmt.Errorf("item header : %v", err)
+	}
+	if item.Type != "event" || item.ContentType != "application/json" || item.Length != len(lines[2]) {
+		return fmt.Errorf("unexpected item header %s, payload length %d", lines[1], len(lines[2]))
+	}
+
+	var event struct {
+		EventID     string   `json:"event_id"`
+		Level       string   `json:"level"`
+		Environment string   `json:"environment"`
+		Fingerprint []string `json:"fingerprint"`
+		Exception   struct {
+			Values []struct {
+				Value string `json:"value"`
+			} `json:"values"`
+		} `json:"exception"`
+	}
+	err = json.Unmarshal(lines[2], &event)
+	if err != nil {
+		return fmt.Errorf("event : %v", err)
+	}
+	if event.EventID != header.EventID || len(event.EventID) != 32 {
+		return fmt.Errorf("event ID '%s' does not match envelope '%s'", event.EventID, header.EventID)
+	}
+	if event.Level != "error" || event.Environment != "example" || len(event.Fingerprint) != 1 {
+		return fmt.Errorf("unexpected event %s", lines[2])
+	}
+	if len(event.Exception.Values) != 1 || event.Exception.Values[0].Value != "payment declined for order 7" {
+		return fmt.Errorf("unexpected exception %s", lines[2])
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package reportx
+
+import "time"
+
+/*
+	ReporterCfg - is setting of error reporter. Occurrences are counted per fingerprint in each Window and the reports
+				  are shipped to the sinks at the end of the window. Every occurrence is counted, details (request context
+				  and attributes) are kept for at most MaxSamples occurrences which are picked with SampleRate.
+	For example;
+		error_report:
+			window: "1m"
+			sample_rate: 0.1
+			max_samples: 5
+			spike_factor: 3
+			spike_threshold: 20
+*/
+type ReporterCfg struct {
+	Window         time.Duration `json:"window" mapstructure:"window"`
+	SampleRate     float64       `json:"sample_rate" mapstructure:"sample_rate"`
+	MaxSamples     int           `json:"max_samples" mapstructure:"max_samples"`
+	BatchSize      int           `json:"batch_size" mapstructure:"batch_size"`
+	StackDepth     int           `json:"stack_depth" mapstructure:"stack_depth"`         // number of frames used for fingerprint
+	SpikeFactor    float64       `json:"spike_factor" mapstructure:"spike_factor"`       // count compared with previous window
+	SpikeThreshold int64         `json:"spike_threshold" mapstructure:"spike_threshold"` // minimum count to be a spike
+	SeenTTL        time.Duration `json:"seen_ttl" mapstructure:"seen_ttl"`               // fingerprint is new again after this period of silence
+	Environment    string        `json:"environment" mapstructure:"environment"`
+	Release        string        `json:"release" mapstructure:"release"`
+	ServerName     string        `json:"server_name" mapstructure:"server_name"`
+}
+
+/*
+	SentrySinkCfg - DSN is in the format of "{scheme}://{public_key}@{host}/{project_id}".
+*/
+type SentrySinkCfg struct {
+	DSN     string        `json:"dsn" mapstructure:"dsn"`
+	Timeout time.Duration `json:"timeout" mapstructure:"timeout"`
+}
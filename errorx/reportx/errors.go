@@ -0,0 +1,25 @@
+package reportx
+
+import "github.com/kyawmyintthein/orange-contrib/errorx"
+
+type InvalidDSNError struct {
+	*errorx.ErrorX
+}
+
+func NewInvalidDSNError(reason string) *InvalidDSNError {
+	return &InvalidDSNError{
+		errorx.NewErrorX("[%s] invalid DSN : %s", PackageName, reason),
+	}
+}
+
+type SinkError struct {
+	*errorx.ErrorX
+	*errorx.ErrorWithRetry
+}
+
+func NewSinkError(url string, statusCode int) *SinkError {
+	return &SinkError{
+		errorx.NewErrorX("[%s] sink returned status code : %d from URL: %s", PackageName, statusCode, url),
+		errorx.NewErrorWithRetry(statusCode == 429 || statusCode >= 500, 0),
+	}
+}
@@ -0,0 +1,111 @@
+package reportx
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+)
+
+const defaultStackDepth int = 8
+
+var (
+	closureSuffix = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+	uuidPattern   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexPattern    = regexp.MustCompile(`\b0x[0-9a-fA-F]+\b|\b[0-9a-fA-F]{16,}\b`)
+	numberPattern = regexp.MustCompile(`\d+`)
+	quotedPattern = regexp.MustCompile(`'[^']*'|"[^"]*"`)
+)
+
+// Frame is a normalized stack frame of the report, frames are ordered from the innermost call.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+/*
+	Fingerprint - groups the occurrences of the same error. It is built from error ID and code of the error chain
+				  plus function names of the origin stack, so that line changes and different values in the message
+				  do not split the group. Error type and message with the values masked are used when the error has
+				  neither ID nor code.
+*/
+func Fingerprint(err error) string {
+	return fingerprint(err, stackFrames(err, defaultStackDepth))
+}
+
+func fingerprint(err error, frames []Frame) string {
+	var (
+		id   string
+		code int
+	)
+	errorx.Walk(err, func(e error) bool {
+		errWithID, ok := e.(errorx.ErrorID)
+		if ok && id == "" {
+			id = errWithID.ID()
+		}
+		errWithCode, ok := e.(errorx.ErrorCode)
+		if ok && code == 0 {
+			code = errWithCode.Code()
+		}
+		return true
+	})
+
+	parts := []string{id, strconv.Itoa(code)}
+	if id == "" && code == 0 {
+		origin := errorx.Cause(err)
+		parts = append(parts, errorType(origin), normalizeMessage(origin.Error()))
+	}
+	for _, frame := range frames {
+		parts = append(parts, frame.Function)
+	}
+
+	sum := sha1.Sum([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// stackFrames returns frames of the innermost stack trace in the chain, which is the closest one to the origin.
+func stackFrames(err error, depth int) []Frame {
+	var stackTracer errorx.StackTracer
+	errorx.Walk(err, func(e error) bool {
+		st, ok := e.(errorx.StackTracer)
+		if ok && len(st.StackFrames()) > 0 {
+			stackTracer = st
+		}
+		return true
+	})
+	if stackTracer == nil {
+		return nil
+	}
+
+	var frames []Frame
+	for _, stackFrame := range stackTracer.StackFrames() {
+		if strings.HasPrefix(stackFrame.FuncName, "runtime.") || strings.HasPrefix(stackFrame.FuncName, "testing.") {
+			continue
+		}
+		frames = append(frames, Frame{
+			Function: closureSuffix.ReplaceAllString(stackFrame.FuncName, ".func"),
+			File:     stackFrame.File,
+			Line:     stackFrame.LineNumber,
+		})
+		if len(frames) == depth {
+			break
+		}
+	}
+	return frames
+}
+
+func normalizeMessage(msg string) string {
+	msg = quotedPattern.ReplaceAllString(msg, "<str>")
+	msg = uuidPattern.ReplaceAllString(msg, "<uuid>")
+	msg = hexPattern.ReplaceAllString(msg, "<hex>")
+	return numberPattern.ReplaceAllString(msg, "<n>")
+}
+
+func errorType(err error) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", err), "*")
+}
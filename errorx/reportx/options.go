@@ -0,0 +1,38 @@
+package reportx
+
+import (
+	"context"
+
+	"github.com/kyawmyintthein/orange-contrib/optionx"
+)
+
+/*
+	WithSink - adds a sink which the reports are shipped to. The option can be given several times.
+*/
+type sinksKey struct{}
+
+func WithSink(sink Sink) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		sinks, _ := o.Context.Value(sinksKey{}).([]Sink)
+		o.Context = context.WithValue(o.Context, sinksKey{}, append(sinks, sink))
+	}
+}
+
+/*
+	WithContextExtractor - adds extractor of request context, e.g. user ID or tenant. Request ID and trace ID are
+						   always extracted.
+*/
+type contextExtractorsKey struct{}
+
+func WithContextExtractor(extractor ContextExtractor) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		extractors, _ := o.Context.Value(contextExtractorsKey{}).([]ContextExtractor)
+		o.Context = context.WithValue(o.Context, contextExtractorsKey{}, append(extractors, extractor))
+	}
+}
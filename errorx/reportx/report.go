@@ -0,0 +1,95 @@
+package reportx
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+	"github.com/kyawmyintthein/orange-contrib/middlewarex"
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
+)
+
+/*
+	Report - is the aggregated occurrences of an error in a window. New is set when the fingerprint is not seen within
+			 SeenTTL, Spike is set when the count grows by SpikeFactor compared with the previous window.
+*/
+type Report struct {
+	Fingerprint   string                 `json:"fingerprint"`
+	ErrorID       string                 `json:"error_id,omitempty"`
+	Code          int                    `json:"code,omitempty"`
+	Type          string                 `json:"type"`
+	Message       string                 `json:"message"`
+	Severity      errorx.Severity        `json:"severity"`
+	Count         int64                  `json:"count"`
+	PreviousCount int64                  `json:"previous_count"`
+	New           bool                   `json:"new"`
+	Spike         bool                   `json:"spike"`
+	FirstSeen     time.Time              `json:"first_seen"`
+	LastSeen      time.Time              `json:"last_seen"`
+	WindowStart   time.Time              `json:"window_start"`
+	WindowEnd     time.Time              `json:"window_end"`
+	Environment   string                 `json:"environment,omitempty"`
+	Release       string                 `json:"release,omitempty"`
+	ServerName    string                 `json:"server_name,omitempty"`
+	Stacktrace    []Frame                `json:"stacktrace,omitempty"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"` // sensitive attributes are not included
+	Samples       []*Event               `json:"samples,omitempty"`
+}
+
+// Event is a sampled occurrence with its request context.
+type Event struct {
+	Timestamp  time.Time              `json:"timestamp"`
+	Message    string                 `json:"message"`
+	Context    map[string]string      `json:"context,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// ContextExtractor returns values of the request context which are attached to the sampled events.
+type ContextExtractor func(context.Context) map[string]string
+
+func defaultContextExtractor(ctx context.Context) map[string]string {
+	values := make(map[string]string)
+	requestID := middlewarex.GetReqID(ctx)
+	if requestID == "" {
+		requestID = middleware.GetReqID(ctx)
+	}
+	if requestID != "" {
+		values["request_id"] = requestID
+	}
+
+	span := opentracing.SpanFromContext(ctx)
+	if span != nil {
+		spanContext, ok := span.Context().(jaeger.SpanContext)
+		if ok {
+			values["trace_id"] = spanContext.TraceID().String()
+		}
+	}
+	return values
+}
+
+func requestContext(r *http.Request) map[string]string {
+	values := map[string]string{
+		"method": r.Method,
+		"path":   r.URL.Path,
+	}
+	if userAgent := r.UserAgent(); userAgent != "" {
+		values["user_agent"] = userAgent
+	}
+	return values
+}
+
+func severity(err error) errorx.Severity {
+	result := errorx.SeverityError
+	errorx.Walk(err, func(e error) bool {
+		errSeverity, ok := e.(errorx.ErrorSeverity)
+		if ok && errSeverity.Severity() != "" {
+			result = errSeverity.Severity()
+			return false
+		}
+		return true
+	})
+	return result
+}
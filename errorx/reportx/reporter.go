@@ -0,0 +1,282 @@
+package reportx
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+	"github.com/kyawmyintthein/orange-contrib/logx"
+	"github.com/kyawmyintthein/orange-contrib/optionx"
+)
+
+const (
+	defaultWindow         time.Duration = time.Minute
+	defaultSampleRate     float64       = 1
+	defaultMaxSamples     int           = 5
+	defaultBatchSize      int           = 50
+	defaultSpikeFactor    float64       = 3
+	defaultSpikeThreshold int64         = 10
+	defaultSeenTTL        time.Duration = 24 * time.Hour
+)
+
+type Reporter interface {
+	// Report counts the error, it does not block on the sinks.
+	Report(ctx context.Context, err error)
+	// ReportRequest is Report with method, path and user agent of the request attached to the sampled event.
+	ReportRequest(r *http.Request, err error)
+	// Flush ships the reports of the current window and starts a new window.
+	Flush(ctx context.Context) error
+	Start()
+	// Stop flushes the current window and closes the sinks which implement io.Closer.
+	Stop()
+}
+
+type reporter struct {
+	cfg        *ReporterCfg
+	sinks      []Sink
+	extractors []ContextExtractor
+
+	mu          sync.Mutex
+	windowStart time.Time
+	aggregates  map[string]*Report
+
+	// seen and previous are only used while flushing
+	flushMu  sync.Mutex
+	seen     map[string]time.Time
+	previous map[string]int64
+
+	lifecycleMu sync.Mutex
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+/*
+	NewReporter - creates error reporter. Reports are written to logx when no sink is given by WithSink.
+				  Defaults are filled in a copy of cfg, the given setting is not changed.
+*/
+func NewReporter(reporterCfg *ReporterCfg, opts ...optionx.Option) Reporter {
+	options := optionx.NewOptions(opts...)
+
+	cfg := *reporterCfg
+
+	if cfg.Window <= 0 {
+		cfg.Window = defaultWindow
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		cfg.SampleRate = defaultSampleRate
+	}
+	if cfg.MaxSamples <= 0 {
+		cfg.MaxSamples = defaultMaxSamples
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.StackDepth <= 0 {
+		cfg.StackDepth = defaultStackDepth
+	}
+	if cfg.SpikeFactor <= 1 {
+		cfg.SpikeFactor = defaultSpikeFactor
+	}
+	if cfg.SpikeThreshold <= 0 {
+		cfg.SpikeThreshold = defaultSpikeThreshold
+	}
+	if cfg.SeenTTL <= 0 {
+		cfg.SeenTTL = defaultSeenTTL
+	}
+
+	sinks, _ := options.Context.Value(sinksKey{}).([]Sink)
+	if len(sinks) == 0 {
+		sinks = []Sink{NewLogSink()}
+	}
+	extractors, _ := options.Context.Value(contextExtractorsKey{}).([]ContextExtractor)
+
+	return &reporter{
+		cfg:         &cfg,
+		sinks:       sinks,
+		extractors:  append([]ContextExtractor{defaultContextExtractor}, extractors...),
+		windowStart: time.Now(),
+		aggregates:  make(map[string]*Report),
+		seen:        make(map[string]time.Time),
+		previous:    make(map[string]int64),
+	}
+}
+
+func (r *reporter) Report(ctx context.Context, err error) {
+	r.report(ctx, err, nil)
+}
+
+func (r *reporter) ReportRequest(req *http.Request, err error) {
+	r.report(req.Context(), err, requestContext(req))
+}
+
+func (r *reporter) report(ctx context.Context, err error, values map[string]string) {
+	if err == nil {
+		return
+	}
+	now := time.Now()
+	frames := stackFrames(err, r.cfg.StackDepth)
+	fingerprint := fingerprint(err, frames)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report, ok := r.aggregates[fingerprint]
+	if !ok {
+		report = r.newReport(err, fingerprint, frames, now)
+		r.aggregates[fingerprint] = report
+	}
+	report.Count++
+	report.LastSeen = now
+	// the first occurrence is always sampled so that every report carries a request context
+	if len(report.Samples) < r.cfg.MaxSamples && (len(report.Samples) == 0 || rand.Float64() < r.cfg.SampleRate) {
+		report.Samples = append(report.Samples, r.newEvent(ctx, err, values, now))
+	}
+}
+
+func (r *reporter) newReport(err error, fingerprint string, frames []Frame, now time.Time) *Report {
+	report := &Report{
+		Fingerprint: fingerprint,
+		Type:        errorType(errorx.Cause(err)),
+		Message:     err.Error(),
+		Severity:    severity(err),
+		FirstSeen:   now,
+		Environment: r.cfg.Environment,
+		Release:     r.cfg.Release,
+		ServerName:  r.cfg.ServerName,
+		Stacktrace:  frames,
+		Attributes:  errorx.PublicAttributes(err),
+	}
+	errorx.Walk(err, func(e error) bool {
+		errWithID, ok := e.(errorx.ErrorID)
+		if ok && report.ErrorID == "" {
+			report.ErrorID = errWithID.ID()
+		}
+		errWithCode, ok := e.(errorx.ErrorCode)
+		if ok && report.Code == 0 {
+			report.Code = errWithCode.Code()
+		}
+		return true
+	})
+	return report
+}
+
+func (r *reporter) newEvent(ctx context.Context, err error, values map[string]string, now time.Time) *Event {
+	event := &Event{
+		Timestamp:  now,
+		Message:    err.Error(),
+		Context:    make(map[string]string),
+		Attributes: errorx.PublicAttributes(err),
+	}
+	for _, extractor := range r.extractors {
+		for key, value := range extractor(ctx) {
+			event.Context[key] = value
+		}
+	}
+	for key, value := range values {
+		event.Context[key] = value
+	}
+	return event
+}
+
+func (r *reporter) Flush(ctx context.Context) error {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	now := time.Now()
+	r.mu.Lock()
+	aggregates := r.aggregates
+	windowStart := r.windowStart
+	r.aggregates = make(map[string]*Report)
+	r.windowStart = now
+	r.mu.Unlock()
+
+	reports := make([]*Report, 0, len(aggregates))
+	previous := make(map[string]int64, len(aggregates))
+	for fingerprint, report := range aggregates {
+		lastSeen, seen := r.seen[fingerprint]
+		report.New = !seen || now.Sub(lastSeen) > r.cfg.SeenTTL
+		report.PreviousCount = r.previous[fingerprint]
+		report.Spike = !report.New && report.Count >= r.cfg.SpikeThreshold &&
+			float64(report.Count) >= r.cfg.SpikeFactor*float64(report.PreviousCount)
+		report.WindowStart = windowStart
+		report.WindowEnd = now
+
+		r.seen[fingerprint] = now
+		previous[fingerprint] = report.Count
+		reports = append(reports, report)
+	}
+	r.previous = previous
+	for fingerprint, lastSeen := range r.seen {
+		if now.Sub(lastSeen) > r.cfg.SeenTTL {
+			delete(r.seen, fingerprint)
+		}
+	}
+
+	var firstErr error
+	for start := 0; start < len(reports); start += r.cfg.BatchSize {
+		end := start + r.cfg.BatchSize
+		if end > len(reports) {
+			end = len(reports)
+		}
+		for _, sink := range r.sinks {
+			err := sink.Send(ctx, reports[start:end])
+			if err != nil {
+				logx.Errorf(ctx, err, "[%s] failed to send %d error reports", PackageName, end-start)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	return firstErr
+}
+
+func (r *reporter) Start() {
+	r.lifecycleMu.Lock()
+	defer r.lifecycleMu.Unlock()
+	if r.stop != nil {
+		return
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run(r.stop, r.done)
+}
+
+func (r *reporter) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(r.cfg.Window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = r.Flush(context.Background())
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (r *reporter) Stop() {
+	r.lifecycleMu.Lock()
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+		r.stop = nil
+		r.done = nil
+	}
+	r.lifecycleMu.Unlock()
+
+	_ = r.Flush(context.Background())
+	for _, sink := range r.sinks {
+		closer, ok := sink.(io.Closer)
+		if ok {
+			err := closer.Close()
+			if err != nil {
+				logx.Errorf(context.Background(), err, "[%s] failed to close sink", PackageName)
+			}
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package reportx
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+)
+
+const (
+	sentryEnvelopeContentType string        = "application/x-sentry-envelope"
+	sentryVersion             string        = "7"
+	defaultSentryTimeout      time.Duration = 5 * time.Second
+)
+
+type sentrySink struct {
+	dsn       string
+	publicKey string
+	endpoint  string
+	client    *http.Client
+}
+
+/*
+	NewSentrySink - sends each report as an event envelope to the envelope endpoint of a Sentry compatible server.
+					Fingerprint of the report is used as the event fingerprint, so that the server groups the events
+					in the same way as the reporter.
+*/
+func NewSentrySink(cfg *SentrySinkCfg) (Sink, error) {
+	dsn, err := url.Parse(cfg.DSN)
+	if err != nil {
+		return nil, NewInvalidDSNError(err.Error())
+	}
+	if dsn.Scheme == "" || dsn.Host == "" {
+		return nil, NewInvalidDSNError("scheme and host are required")
+	}
+	if dsn.User == nil || dsn.User.Username() == "" {
+		return nil, NewInvalidDSNError("public key is required")
+	}
+	path := strings.TrimRight(dsn.Path, "/")
+	index := strings.LastIndex(path, "/")
+	projectID := path[index+1:]
+	if projectID == "" {
+		return nil, NewInvalidDSNError("project ID is required")
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultSentryTimeout
+	}
+	return &sentrySink{
+		dsn:       cfg.DSN,
+		publicKey: dsn.User.Username(),
+		endpoint:  fmt.Sprintf("%s://%s%s/api/%s/envelope/", dsn.Scheme, dsn.Host, path[:index], projectID),
+		client:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Platform    string                 `json:"platform"`
+	Level       string                 `json:"level"`
+	Logger      string                 `json:"logger"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	Fingerprint []string               `json:"fingerprint"`
+	Exception   sentryExceptions       `json:"exception"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+}
+
+func (s *sentrySink) Send(ctx context.Context, reports []*Report) error {
+	for _, report := range reports {
+		err := s.send(ctx, report)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sentrySink) send(ctx context.Context, report *Report) error {
+	event := s.event(report)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// envelope is newline separated: envelope header, item header and item payload
+	var body bytes.Buffer
+	envelopeHeader, _ := json.Marshal(map[string]string{
+		"event_id": event.EventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339),
+		"dsn":      s.dsn,
+	})
+	itemHeader, _ := json.Marshal(map[string]interface{}{
+		"type":         "event",
+		"length":       len(payload),
+		"content_type": "application/json",
+	})
+	body.Write(envelopeHeader)
+	body.WriteByte('\n')
+	body.Write(itemHeader)
+	body.WriteByte('\n')
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", sentryEnvelopeContentType)
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=%s, sentry_client=orange-contrib-reportx/%s, sentry_key=%s", sentryVersion, Version, s.publicKey))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errorx.Wrap(err, "failed to send error report")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return NewSinkError(s.endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *sentrySink) event(report *Report) *sentryEvent {
+	event := &sentryEvent{
+		EventID:     newEventID(),
+		Timestamp:   report.LastSeen.UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       sentryLevel(report.Severity),
+		Logger:      PackageName,
+		ServerName:  report.ServerName,
+		Environment: report.Environment,
+		Release:     report.Release,
+		Fingerprint: []string{report.Fingerprint},
+		Tags:        make(map[string]string),
+		Extra: map[string]interface{}{
+			"count":          report.Count,
+			"previous_count": report.PreviousCount,
+			"new":            report.New,
+			"spike":          report.Spike,
+			"first_seen":     report.FirstSeen,
+			"window_start":   report.WindowStart,
+			"window_end":     report.WindowEnd,
+		},
+	}
+	if report.ErrorID != "" {
+		event.Tags["error_id"] = report.ErrorID
+	}
+	if report.Code != 0 {
+		event.Tags["error_code"] = strconv.Itoa(report.Code)
+	}
+	if len(report.Attributes) > 0 {
+		event.Extra["attributes"] = report.Attributes
+	}
+	if len(report.Samples) > 0 {
+		for key, value := range report.Samples[0].Context {
+			event.Tags[key] = value
+		}
+		event.Extra["samples"] = report.Samples
+	}
+
+	exception := sentryException{
+		Type:  report.Type,
+		Value: report.Message,
+	}
+	if len(report.Stacktrace) > 0 {
+		// Sentry expects the frames from the outermost call
+		frames := make([]sentryFrame, 0, len(report.Stacktrace))
+		for i := len(report.Stacktrace) - 1; i >= 0; i-- {
+			frame := report.Stacktrace[i]
+			frames = append(frames, sentryFrame{Function: frame.Function, Filename: frame.File, Lineno: frame.Line})
+		}
+		exception.Stacktrace = &sentryStacktrace{Frames: frames}
+	}
+	event.Exception.Values = []sentryException{exception}
+	return event
+}
+
+func sentryLevel(severity errorx.Severity) string {
+	switch severity {
+	case errorx.SeverityCritical:
+		return "fatal"
+	case errorx.SeverityWarning:
+		return "warning"
+	case errorx.SeverityInfo:
+		return "info"
+	case errorx.SeverityDebug:
+		return "debug"
+	default:
+		return "error"
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
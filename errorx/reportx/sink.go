@@ -0,0 +1,70 @@
+package reportx
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/kyawmyintthein/orange-contrib/logx"
+)
+
+// Sink ships a batch of reports. Sinks which implement io.Closer are closed when the reporter is stopped.
+type Sink interface {
+	Send(ctx context.Context, reports []*Report) error
+}
+
+type logSink struct{}
+
+// NewLogSink writes a warning log per report.
+func NewLogSink() Sink {
+	return &logSink{}
+}
+
+func (s *logSink) Send(ctx context.Context, reports []*Report) error {
+	for _, report := range reports {
+		logx.WarnKVf(ctx, logx.KV{
+			"Fingerprint": report.Fingerprint,
+			"ErrorID":     report.ErrorID,
+			"Code":        report.Code,
+			"Severity":    report.Severity,
+			"Count":       report.Count,
+			"New":         report.New,
+			"Spike":       report.Spike,
+		}, "[%s] error occurred %d times : %s", PackageName, report.Count, report.Message)
+	}
+	return nil
+}
+
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink appends each report as a line of JSON to the file.
+func NewFileSink(path string) (Sink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Send(ctx context.Context, reports []*Report) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	encoder := json.NewEncoder(s.file)
+	for _, report := range reports {
+		err := encoder.Encode(report)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
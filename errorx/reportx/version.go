@@ -0,0 +1,6 @@
+package reportx
+
+const (
+	PackageName = "ReportX"
+	Version     = "v0.0.1"
+)
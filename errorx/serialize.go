@@ -0,0 +1,294 @@
+package errorx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const maxSerializedChain int = 64
+
+/*
+	SerializedError - is the stable JSON structure of an error chain, used to persist errors (e.g. in failed job records)
+					  and to pass them across process boundaries. Retryable, Timeout, Temporary and RetryAfter are the
+					  classification of the chain starting from this error, so that the decoded error is classified the
+					  same way. Sensitive attributes are not serialized. For example;
+						{
+							"type": "errorx.WrappedError",
+							"message": "failed to charge order 7, card declined",
+							"format": "failed to charge order %d",
+							"args": [7],
+							"formatted_message": "failed to charge order 7",
+							"attributes": [{"key": "order_id", "value": 7}],
+							"stack": [{"function": "main.charge", "file": "/app/main.go", "line": 42}],
+							"cause": {"type": "*errors.errorString", "message": "card declined"}
+						}
+*/
+type SerializedError struct {
+	Type             string                `json:"type,omitempty"`
+	Message          string                `json:"message"`
+	Format           string                `json:"format,omitempty"`
+	Args             []interface{}         `json:"args,omitempty"`
+	FormattedMessage string                `json:"formatted_message,omitempty"`
+	Code             int                   `json:"code,omitempty"`
+	ID               string                `json:"id,omitempty"`
+	Status           int                   `json:"status,omitempty"`
+	Severity         Severity              `json:"severity,omitempty"`
+	Field            string                `json:"field,omitempty"`
+	Retryable        bool                  `json:"retryable,omitempty"`
+	Timeout          bool                  `json:"timeout,omitempty"`
+	Temporary        bool                  `json:"temporary,omitempty"`
+	RetryAfterMillis int64                 `json:"retry_after_ms,omitempty"`
+	Attributes       []SerializedAttribute `json:"attributes,omitempty"`
+	Stack            []SerializedFrame     `json:"stack,omitempty"`
+	Errors           []*SerializedError    `json:"errors,omitempty"` // errors of MultiError
+	Cause            *SerializedError      `json:"cause,omitempty"`
+}
+
+type SerializedAttribute struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+type SerializedFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Serialize converts the error and its cause chain into SerializedError. nil is returned if err is nil.
+func Serialize(err error) *SerializedError {
+	return serialize(err, 0)
+}
+
+func serialize(err error, depth int) *SerializedError {
+	if err == nil || depth >= maxSerializedChain {
+		return nil
+	}
+
+	serialized := &SerializedError{
+		Type:      fmt.Sprintf("%T", err),
+		Message:   err.Error(),
+		Retryable: IsRetryable(err),
+		Timeout:   IsTimeout(err),
+		Temporary: IsTemporary(err),
+	}
+	decoded, ok := err.(*DecodedError)
+	if ok && decoded.typeName != "" {
+		serialized.Type = decoded.typeName
+	}
+	retryAfter, ok := RetryAfter(err)
+	if ok {
+		serialized.RetryAfterMillis = int64(retryAfter / time.Millisecond)
+	}
+
+	errorFormatter, ok := err.(ErrorFormatter)
+	if ok {
+		serialized.Format = errorFormatter.GetMessage()
+		serialized.FormattedMessage = errorFormatter.FormattedMessage()
+		for _, arg := range errorFormatter.GetArgs() {
+			serialized.Args = append(serialized.Args, serializeArg(arg))
+		}
+	}
+	if decoded != nil && decoded.formattedMessage == "" {
+		// the original error was not an ErrorFormatter
+		serialized.Format = ""
+		serialized.Args = nil
+		serialized.FormattedMessage = ""
+	}
+	errWithCode, ok := err.(ErrorCode)
+	if ok {
+		serialized.Code = errWithCode.Code()
+	}
+	errWithID, ok := err.(ErrorID)
+	if ok {
+		serialized.ID = errWithID.ID()
+	}
+	httpError, ok := err.(HttpError)
+	if ok {
+		serialized.Status = httpError.StatusCode()
+	}
+	errSeverity, ok := err.(ErrorSeverity)
+	if ok {
+		serialized.Severity = errSeverity.Severity()
+	}
+	errorField, ok := err.(ErrorField)
+	if ok {
+		serialized.Field = errorField.Field()
+	}
+	errWithAttributes, ok := err.(ErrorAttributes)
+	if ok {
+		for _, attr := range errWithAttributes.Attributes() {
+			if attr.Sensitive {
+				continue
+			}
+			serialized.Attributes = append(serialized.Attributes, SerializedAttribute{Key: attr.Key, Value: serializeArg(attr.Value)})
+		}
+	}
+	stackTracer, ok := err.(StackTracer)
+	if ok {
+		for _, frame := range stackTracer.StackFrames() {
+			serialized.Stack = append(serialized.Stack, SerializedFrame{Function: frame.FuncName, File: frame.File, Line: frame.LineNumber})
+		}
+	}
+	multiError, ok := err.(MultiError)
+	if ok {
+		for _, e := range multiError.Errors() {
+			serialized.Errors = append(serialized.Errors, serialize(e, depth+1))
+		}
+	}
+	serialized.Cause = serialize(Next(err), depth+1)
+	return serialized
+}
+
+// serializeArg keeps the values which survive JSON round trip as they are, the others are formatted with %v.
+func serializeArg(arg interface{}) interface{} {
+	switch v := arg.(type) {
+	case nil, bool, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case json.Number:
+		// numbers of a decoded error are written as numbers again, so that the format is stable across hops
+		return v
+	case error:
+		return v.Error()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// MarshalError encodes the error chain in the SerializedError structure.
+func MarshalError(err error) ([]byte, error) {
+	return json.Marshal(Serialize(err))
+}
+
+// UnmarshalError decodes the data produced by MarshalError into DecodedError. nil is returned for JSON null.
+func UnmarshalError(data []byte) (error, error) {
+	var serialized *SerializedError
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // keeps integer arguments and attributes precise
+	err := decoder.Decode(&serialized)
+	if err != nil {
+		return nil, err
+	}
+	if serialized == nil {
+		return nil, nil
+	}
+	return Deserialize(serialized), nil
+}
+
+/*
+	DecodedError - is the generic error restored from SerializedError. It implements all the errorx interfaces, so that
+				   code, ID, status, severity, attributes, stack trace and classification can be read as usual,
+				   and errors.Is matches it by ID or code. The original Go type is kept in Type.
+*/
+type DecodedError struct {
+	*ErrorX
+	*ErrorWithCode
+	*ErrorWithID
+	*ErrorWithHttpStatus
+	*ErrorWithSeverity
+	*ErrorWithAttributes
+	*ErrorStacktrace
+	typeName         string
+	message          string
+	formattedMessage string
+	field            string
+	retryable        bool
+	timeout          bool
+	temporary        bool
+	retryAfter       time.Duration
+	errors           []error
+}
+
+// Deserialize restores the error chain, nil is returned if serialized is nil.
+func Deserialize(serialized *SerializedError) *DecodedError {
+	if serialized == nil {
+		return nil
+	}
+
+	frames := make([]StackFrame, 0, len(serialized.Stack))
+	for _, frame := range serialized.Stack {
+		frames = append(frames, StackFrame{FuncName: frame.Function, File: frame.File, LineNumber: frame.Line})
+	}
+	attrs := make([]Attribute, 0, len(serialized.Attributes))
+	for _, attr := range serialized.Attributes {
+		attrs = append(attrs, Attr(attr.Key, attr.Value))
+	}
+
+	decoded := &DecodedError{
+		ErrorX:              NewErrorX(serialized.Format, serialized.Args...),
+		ErrorWithCode:       NewErrorWithCode(serialized.Code),
+		ErrorWithID:         NewErrorWithID(serialized.ID),
+		ErrorWithHttpStatus: NewErrorWithHttpStatus(serialized.Status),
+		ErrorWithSeverity:   NewErrorWithSeverity(serialized.Severity),
+		ErrorWithAttributes: NewErrorWithAttributes(attrs...),
+		ErrorStacktrace:     newStacktraceFromFrames(frames),
+		typeName:            serialized.Type,
+		message:             serialized.Message,
+		formattedMessage:    serialized.FormattedMessage,
+		field:               serialized.Field,
+		retryable:           serialized.Retryable,
+		timeout:             serialized.Timeout,
+		temporary:           serialized.Temporary,
+		retryAfter:          time.Duration(serialized.RetryAfterMillis) * time.Millisecond,
+	}
+	for _, e := range serialized.Errors {
+		if e != nil {
+			decoded.errors = append(decoded.errors, Deserialize(e))
+		}
+	}
+	if serialized.Cause != nil {
+		decoded.Wrap(Deserialize(serialized.Cause))
+	}
+	return decoded
+}
+
+func (err *DecodedError) Error() string {
+	return err.message
+}
+
+// FormattedMessage returns the message as it was formatted before serialization, arguments may have changed their types.
+func (err *DecodedError) FormattedMessage() string {
+	if err.formattedMessage == "" {
+		return err.message
+	}
+	return err.formattedMessage
+}
+
+// Type returns the Go type of the original error, e.g. "*errorx.WrappedError".
+func (err *DecodedError) Type() string {
+	return err.typeName
+}
+
+func (err *DecodedError) Field() string {
+	return err.field
+}
+
+func (err *DecodedError) Errors() []error {
+	return err.errors
+}
+
+func (err *DecodedError) Retryable() bool {
+	return err.retryable
+}
+
+func (err *DecodedError) Timeout() bool {
+	return err.timeout
+}
+
+func (err *DecodedError) Temporary() bool {
+	return err.temporary
+}
+
+func (err *DecodedError) RetryAfter() time.Duration {
+	return err.retryAfter
+}
+
+func (err *DecodedError) Is(target error) bool {
+	return Match(err, target)
+}
+
+func (err *DecodedError) Format(s fmt.State, verb rune) {
+	FormatError(err, s, verb)
+}
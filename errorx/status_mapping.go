@@ -0,0 +1,237 @@
+package errorx
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const (
+	defaultPrefixDigits int = 3
+	defaultCodeDigits   int = 6
+)
+
+var (
+	statusMappingMu sync.RWMutex
+	statusMapping   StatusMapping = PrefixMapping{}
+)
+
+/*
+	StatusMapping - maps error code to http status and http status back to error code. The reverse mapping is used
+					when an upstream response carries a status without error code. The second return value is false
+					if the mapping does not know the code or the status.
+*/
+type StatusMapping interface {
+	StatusCode(code int) (int, bool)
+	ErrorCode(status int) (int, bool)
+}
+
+/*
+	StatusMappingCfg - Codes are checked first, then Ranges, then the prefix scheme unless DisablePrefix is set.
+	For example;
+		status_mapping:
+			codes:
+				100001: 409
+			ranges:
+				- from: 200000
+				  to: 299999
+				  status: 400
+			code_digits: 6
+*/
+type StatusMappingCfg struct {
+	Codes         map[int]int   `json:"codes" yaml:"codes" mapstructure:"codes"`
+	Ranges        []StatusRange `json:"ranges" yaml:"ranges" mapstructure:"ranges"`
+	PrefixDigits  int           `json:"prefix_digits" yaml:"prefix_digits" mapstructure:"prefix_digits"` // default 3
+	CodeDigits    int           `json:"code_digits" yaml:"code_digits" mapstructure:"code_digits"`       // length of reverse mapped code, default 6
+	DisablePrefix bool          `json:"disable_prefix" yaml:"disable_prefix" mapstructure:"disable_prefix"`
+}
+
+// StatusRange maps the codes from From to To (inclusive) to Status.
+type StatusRange struct {
+	From   int `json:"from" yaml:"from" mapstructure:"from"`
+	To     int `json:"to" yaml:"to" mapstructure:"to"`
+	Status int `json:"status" yaml:"status" mapstructure:"status"`
+}
+
+/*
+	PrefixMapping - is the default scheme, the leading digits of the code are the http status, e.g. 404001 is 404.
+					Codes which are shorter than the prefix or whose prefix is not a valid status are not mapped.
+					Status is reverse mapped to the first code of the status, e.g. 404 is 404000 with 6 digits code.
+*/
+type PrefixMapping struct {
+	Digits     int
+	CodeDigits int
+}
+
+func (m PrefixMapping) StatusCode(code int) (int, bool) {
+	digits := m.Digits
+	if digits <= 0 {
+		digits = defaultPrefixDigits
+	}
+	if code <= 0 {
+		return 0, false
+	}
+	str := strconv.Itoa(code)
+	if len(str) < digits {
+		return 0, false
+	}
+	status, err := strconv.Atoi(str[:digits])
+	if err != nil || !validHttpStatus(status) {
+		return 0, false
+	}
+	return status, true
+}
+
+func (m PrefixMapping) ErrorCode(status int) (int, bool) {
+	digits, codeDigits := m.Digits, m.CodeDigits
+	if digits <= 0 {
+		digits = defaultPrefixDigits
+	}
+	if codeDigits <= 0 {
+		codeDigits = defaultCodeDigits
+	}
+	if !validHttpStatus(status) {
+		return 0, false
+	}
+	code := status
+	for i := digits; i < codeDigits; i++ {
+		code *= 10
+	}
+	return code, true
+}
+
+// RangeMapping - the first range containing the code wins, status is reverse mapped to From of the first range.
+type RangeMapping []StatusRange
+
+func (m RangeMapping) StatusCode(code int) (int, bool) {
+	for _, r := range m {
+		if code >= r.From && code <= r.To {
+			return r.Status, true
+		}
+	}
+	return 0, false
+}
+
+func (m RangeMapping) ErrorCode(status int) (int, bool) {
+	for _, r := range m {
+		if r.Status == status {
+			return r.From, true
+		}
+	}
+	return 0, false
+}
+
+// CodeMapping - explicit code to status map, status is reverse mapped to the smallest code of the status.
+type CodeMapping map[int]int
+
+func (m CodeMapping) StatusCode(code int) (int, bool) {
+	status, ok := m[code]
+	return status, ok
+}
+
+func (m CodeMapping) ErrorCode(status int) (int, bool) {
+	codes := make([]int, 0, len(m))
+	for code, s := range m {
+		if s == status {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return 0, false
+	}
+	sort.Ints(codes)
+	return codes[0], true
+}
+
+type chainMapping []StatusMapping
+
+// ChainMappings returns a mapping which asks the given mappings in order until one of them knows the code or status.
+func ChainMappings(mappings ...StatusMapping) StatusMapping {
+	return chainMapping(mappings)
+}
+
+func (m chainMapping) StatusCode(code int) (int, bool) {
+	for _, mapping := range m {
+		status, ok := mapping.StatusCode(code)
+		if ok {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+func (m chainMapping) ErrorCode(status int) (int, bool) {
+	for _, mapping := range m {
+		code, ok := mapping.ErrorCode(status)
+		if ok {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// NewStatusMapping builds the mapping of the setting, statuses must be valid http status.
+func NewStatusMapping(cfg *StatusMappingCfg) (StatusMapping, error) {
+	var mappings []StatusMapping
+	if len(cfg.Codes) > 0 {
+		for code, status := range cfg.Codes {
+			if !validHttpStatus(status) {
+				return nil, NewErrorX("invalid http status %d of error code %d", status, code)
+			}
+		}
+		mappings = append(mappings, CodeMapping(cfg.Codes))
+	}
+	if len(cfg.Ranges) > 0 {
+		for _, r := range cfg.Ranges {
+			if r.From > r.To {
+				return nil, NewErrorX("invalid error code range %d - %d", r.From, r.To)
+			}
+			if !validHttpStatus(r.Status) {
+				return nil, NewErrorX("invalid http status %d of error code range %d - %d", r.Status, r.From, r.To)
+			}
+		}
+		mappings = append(mappings, RangeMapping(cfg.Ranges))
+	}
+	if !cfg.DisablePrefix {
+		mappings = append(mappings, PrefixMapping{Digits: cfg.PrefixDigits, CodeDigits: cfg.CodeDigits})
+	}
+	if len(mappings) == 1 {
+		return mappings[0], nil
+	}
+	return ChainMappings(mappings...), nil
+}
+
+// SetStatusMapping replaces the global mapping used by HttpStatusFromCode and ErrorCodeFromHttpStatus.
+func SetStatusMapping(mapping StatusMapping) {
+	statusMappingMu.Lock()
+	defer statusMappingMu.Unlock()
+	statusMapping = mapping
+}
+
+func GetStatusMapping() StatusMapping {
+	statusMappingMu.RLock()
+	defer statusMappingMu.RUnlock()
+	return statusMapping
+}
+
+// HttpStatusFromCode maps the code with the global mapping, DefaultHttpStatusCode is returned if it is not mapped.
+func HttpStatusFromCode(code int) int {
+	status, ok := GetStatusMapping().StatusCode(code)
+	if !ok {
+		return DefaultHttpStatusCode
+	}
+	return status
+}
+
+// ErrorCodeFromHttpStatus maps the status back to error code with the global mapping, 0 is returned if it is not mapped.
+func ErrorCodeFromHttpStatus(status int) int {
+	code, ok := GetStatusMapping().ErrorCode(status)
+	if !ok {
+		return 0
+	}
+	return code
+}
+
+func validHttpStatus(status int) bool {
+	return status >= 100 && status <= 599
+}
@@ -0,0 +1,85 @@
+package errorx
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	WireContentType string = "application/json"
+)
+
+/*
+	WireError - is the JSON representation of an error exchanged between services, so that error code, ID and
+				http status survive service hops. The cause chain is kept in Cause. For example;
+					{"error": {"code": 404001, "id": "user_not_found", "message": "user not found", "status": 404}}
+*/
+type WireError struct {
+	Code       int                    `json:"code,omitempty"`
+	ID         string                 `json:"id,omitempty"`
+	Message    string                 `json:"message"`
+	Status     int                    `json:"status,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"` // sensitive attributes are not included
+	Cause      *WireError             `json:"cause,omitempty"`
+}
+
+type WireEnvelope struct {
+	Error *WireError `json:"error"`
+}
+
+// ToWire converts error and its causes into WireError.
+func ToWire(err error) *WireError {
+	if err == nil {
+		return nil
+	}
+
+	wireErr := &WireError{
+		Message: err.Error(),
+	}
+	errorFormatter, ok := err.(ErrorFormatter)
+	if ok {
+		wireErr.Message = errorFormatter.FormattedMessage()
+	}
+	errWithCode, ok := err.(ErrorCode)
+	if ok {
+		wireErr.Code = errWithCode.Code()
+	}
+	errWithID, ok := err.(ErrorID)
+	if ok {
+		wireErr.ID = errWithID.ID()
+	}
+	httpError, ok := err.(HttpError)
+	if ok {
+		wireErr.Status = httpError.StatusCode()
+	}
+	errWithAttributes, ok := err.(ErrorAttributes)
+	if ok {
+		for _, attr := range errWithAttributes.Attributes() {
+			if attr.Sensitive {
+				continue
+			}
+			if wireErr.Attributes == nil {
+				wireErr.Attributes = make(map[string]interface{})
+			}
+			wireErr.Attributes[attr.Key] = attr.Value
+		}
+	}
+	errorCauser, ok := err.(Causer)
+	if ok {
+		wireErr.Cause = ToWire(errorCauser.Cause())
+	}
+	return wireErr
+}
+
+// WriteHttpError writes the error in wire format with its http status, 500 is used if the error has no http status.
+func WriteHttpError(w http.ResponseWriter, err error) error {
+	wireErr := ToWire(err)
+	status := DefaultHttpStatusCode
+	if wireErr != nil && wireErr.Status != 0 {
+		status = wireErr.Status
+	}
+
+	w.Header().Set("Content-Type", WireContentType)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(WireEnvelope{Error: wireErr})
+}
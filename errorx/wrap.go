@@ -0,0 +1,44 @@
+package errorx
+
+import "fmt"
+
+// WrappedError is returned by Wrap and Wrapf, it keeps the message and the stack at the wrap point.
+type WrappedError struct {
+	*ErrorX
+	*ErrorStacktrace
+	*ErrorWithAttributes
+}
+
+// Wrap annotates err with message and captures the stack. nil is returned if err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return newWrappedError(err, NewErrorX("%s", message))
+}
+
+// Wrapf annotates err with formatted message and captures the stack. nil is returned if err is nil.
+func Wrapf(err error, messageFormat string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return newWrappedError(err, NewErrorX(messageFormat, args...))
+}
+
+func newWrappedError(err error, base *ErrorX) *WrappedError {
+	base.Wrap(err)
+	return &WrappedError{
+		base,
+		CaptureStack(2),
+		NewErrorWithAttributes(),
+	}
+}
+
+// Error returns the message of the wrap point followed by the messages of the wrapped errors.
+func (err *WrappedError) Error() string {
+	return GetErrorMessages(err)
+}
+
+func (err *WrappedError) Format(s fmt.State, verb rune) {
+	FormatError(err, s, verb)
+}
@@ -1,6 +1,6 @@
 module github.com/kyawmyintthein/orange-contrib
 
-go 1.14
+go 1.15
 
 require (
 	github.com/afex/hystrix-go v0.0.0-20180502004556-fa1af6a1f4f5
@@ -16,4 +16,5 @@ require (
 	github.com/uber/jaeger-client-go v2.25.0+incompatible
 	github.com/uber/jaeger-lib v2.2.0+incompatible // indirect
 	go.uber.org/atomic v1.6.0 // indirect
+	gopkg.in/yaml.v2 v2.2.8
 )
@@ -0,0 +1,184 @@
+package clientx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/kyawmyintthein/orange-contrib/logx"
+	"github.com/kyawmyintthein/orange-contrib/optionx"
+	"github.com/kyawmyintthein/orange-contrib/tracingx/jaegerx"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	defaultBatchConcurrency int = 10
+)
+
+type BatchRequest struct {
+	Method  string
+	URL     string
+	Body    io.Reader
+	Options []optionx.Option
+}
+
+// BatchResult - Response is kept even when the upstream returns 5xx status code, response body must be closed by the caller.
+type BatchResult struct {
+	Response *http.Response
+	Err      error
+}
+
+type BatchExecutor interface {
+	/*
+		Batch - executes the requests concurrently and returns the results in the same order of the requests.
+				Error is *BatchError when one or more requests fail. Options are WithOpName for the parent span name,
+				WithBatchConcurrency, WithBatchHostConcurrency and WithBatchFailFast.
+	*/
+	Batch(context.Context, []BatchRequest, ...optionx.Option) ([]BatchResult, error)
+}
+
+func (httpClient *httpClient) Batch(ctx context.Context, requests []BatchRequest, opts ...optionx.Option) ([]BatchResult, error) {
+	options := optionx.NewOptions(opts...)
+	concurrency, ok := options.Context.Value(batchConcurrencyKey{}).(int)
+	if !ok || concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	hostConcurrency, _ := options.Context.Value(batchHostConcurrencyKey{}).(int)
+	failFast, _ := options.Context.Value(batchFailFastKey{}).(bool)
+	operationName, ok := options.Context.Value(operationNameKey{}).(string)
+	if !ok || operationName == "" {
+		operationName = "batch"
+	}
+
+	spanStarter, ok := httpClient.jaegerTracer.(jaegerx.SpanStarter)
+	if !httpClient.config.TurnOffJaeger && ok && httpClient.jaegerTracer.IsEnabled() {
+		var span opentracing.Span
+		span, ctx = spanStarter.StartSpan(ctx, operationName)
+		if span != nil {
+			span.SetTag("batch.size", len(requests))
+			defer span.Finish()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		results   = make([]BatchResult, len(requests))
+		failures  = make(map[int]error)
+		limiter   = make(chan struct{}, concurrency)
+		hostLimit = newHostLimiter(hostConcurrency)
+	)
+
+	for i := range requests {
+		limiter <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-limiter }()
+
+			release := hostLimit.acquire(ctx, httpClient.hostOf(requests[i].URL))
+			defer release()
+
+			// requests which are not started yet are skipped after fail fast cancelled the batch
+			if ctx.Err() != nil {
+				results[i] = BatchResult{Err: ctx.Err()}
+				return
+			}
+
+			resp, err := httpClient.do(ctx, requests[i])
+			if err == nil && resp != nil && resp.StatusCode >= http.StatusInternalServerError {
+				err = NewServerError(httpClient.resolve(requests[i].URL), resp.StatusCode)
+			}
+			results[i] = BatchResult{Response: resp, Err: err}
+			if err == nil {
+				return
+			}
+
+			mu.Lock()
+			failures[i] = err
+			mu.Unlock()
+			if failFast {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(failures) == 0 {
+		return results, nil
+	}
+	err := NewBatchError(len(requests), failures)
+	logx.WarnKVf(ctx, logx.KV{"OperationName": operationName, "Failed": len(failures), "Total": len(requests)}, "[%s] %s", PackageName, err.Error())
+	return results, err
+}
+
+func (httpClient *httpClient) do(ctx context.Context, req BatchRequest) (*http.Response, error) {
+	switch strings.ToUpper(req.Method) {
+	case httpGetMethod, "":
+		return httpClient.GET(ctx, req.URL, req.Options...)
+	case httpPostMethod:
+		return httpClient.POST(ctx, req.URL, req.Body, req.Options...)
+	case httpPutMethod:
+		return httpClient.PUT(ctx, req.URL, req.Body, req.Options...)
+	case httpPatchMethod:
+		return httpClient.PATCH(ctx, req.URL, req.Body, req.Options...)
+	case httpDeleteMethod:
+		return httpClient.DELETE(ctx, req.URL, req.Body, req.Options...)
+	default:
+		return nil, NewUnsupportedMethodError(req.Method)
+	}
+}
+
+func (httpClient *httpClient) resolve(path string) string {
+	return resolveURL(httpClient.config.BaseURL, path)
+}
+
+func (httpClient *httpClient) hostOf(path string) string {
+	u, err := url.Parse(httpClient.resolve(path))
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+type hostLimiter struct {
+	limit int
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{
+		limit: limit,
+		hosts: make(map[string]chan struct{}),
+	}
+}
+
+// acquire waits for a free slot of the host and returns the function to release it.
+func (l *hostLimiter) acquire(ctx context.Context, host string) func() {
+	if l.limit <= 0 {
+		return func() {}
+	}
+
+	l.mu.Lock()
+	slots, ok := l.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.hosts[host] = slots
+	}
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }
+	case <-ctx.Done():
+		return func() {}
+	}
+}
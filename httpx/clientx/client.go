@@ -8,13 +8,16 @@ import (
 	"io/ioutil"
 	"math"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/afex/hystrix-go/hystrix"
+	"github.com/kyawmyintthein/orange-contrib/errorx"
 	"github.com/kyawmyintthein/orange-contrib/logx"
 	"github.com/kyawmyintthein/orange-contrib/optionx"
 	"github.com/kyawmyintthein/orange-contrib/tracingx/jaegerx"
 	"github.com/kyawmyintthein/orange-contrib/tracingx/newrelicx"
+	newrelic "github.com/newrelic/go-agent"
 	"github.com/opentracing-contrib/go-stdlib/nethttp"
 	"github.com/opentracing/opentracing-go"
 )
@@ -44,6 +47,7 @@ const (
 	defaultRequestTimeout  time.Duration = 10
 	defaultRetryAttempts   uint          = 3
 	defaultBackOffDuration time.Duration = 100 * time.Millisecond
+	defaultMaxRetryAfter   time.Duration = 5 * time.Second
 )
 
 var (
@@ -72,13 +76,86 @@ type httpClient struct {
 	config         *HttpClientCfg
 	jaegerTracer   jaegerx.JaegerTracer
 	newrelicTracer newrelicx.NewrelicTracer
+	singleflight   *singleflightGroup
+	faultInjection *faultInjectionTransport
+	tlsReloader    *certificateReloader
+	dnsCache       *cachingResolver
+	upstream       string
+	transport      http.RoundTripper
+	httpTransport  *http.Transport
+	calls          callTracker
 }
 
+/*
+	NewHttpClient - creates http client of the setting. If mutual TLS is enabled and the client certificate can not be
+					loaded, the error is logged and every request of the client fails with it, so that the client is
+					never downgraded to plain TLS. Use BuildHttpClient to get the error instead.
+*/
 func NewHttpClient(cfg *HttpClientCfg, opts ...optionx.Option) HttpClient {
+	httpClient, err := newHttpClient(cfg, opts...)
+	if err != nil {
+		logx.Errorf(context.Background(), err, "[%s] failed to load client certificate, requests of the client are rejected", PackageName)
+	}
+	return httpClient
+}
+
+// BuildHttpClient - creates http client of the setting and returns error if mutual TLS can not be set up.
+func BuildHttpClient(cfg *HttpClientCfg, opts ...optionx.Option) (HttpClient, error) {
+	httpClient, err := newHttpClient(cfg, opts...)
+	if err != nil {
+		httpClient.Close()
+		return nil, err
+	}
+	return httpClient, nil
+}
+
+// newHttpClient returns the client even if mutual TLS fails, its requests fail with the error in that case.
+func newHttpClient(cfg *HttpClientCfg, opts ...optionx.Option) (*httpClient, error) {
 	options := optionx.NewOptions(opts...)
 
 	httpClient := &httpClient{
-		config: cfg,
+		config:       cfg,
+		singleflight: newSingleflightGroup(),
+	}
+
+	var tlsErr error
+	var baseTransport http.RoundTripper = http.DefaultTransport
+	if cfg.TLSSetting.Enabled || cfg.DNSCacheSetting.Enabled {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.TLSSetting.Enabled {
+			tlsReloader, err := newCertificateReloader(&cfg.TLSSetting)
+			if err != nil {
+				tlsErr = err
+			} else {
+				transport.TLSClientConfig = tlsReloader.tlsConfig()
+				tlsReloader.transport = transport
+				httpClient.tlsReloader = tlsReloader
+			}
+		}
+		if cfg.DNSCacheSetting.Enabled {
+			httpClient.dnsCache = newCachingResolver(&cfg.DNSCacheSetting)
+			transport.DialContext = httpClient.dnsCache.dialContext
+		}
+		httpClient.httpTransport = transport
+		baseTransport = transport
+	}
+	if tlsErr != nil {
+		baseTransport = &failingTransport{err: tlsErr}
+	}
+
+	// set contract recorder
+	recorder, ok := options.Context.Value(contractRecorderKey{}).(InteractionRecorder)
+	if recorder != nil && ok {
+		baseTransport = &recordingTransport{next: baseTransport, recorder: recorder}
+	}
+
+	// the fault injection layer is always installed so that it can be turned on by reloading the setting
+	httpClient.faultInjection = newFaultInjectionTransport(baseTransport, cfg.FaultInjectionSetting)
+	httpClient.transport = httpClient.faultInjection
+
+	upstream, ok := options.Context.Value(upstreamNameKey{}).(string)
+	if ok {
+		httpClient.upstream = upstream
 	}
 
 	//set newrelic
@@ -99,16 +176,78 @@ func NewHttpClient(cfg *HttpClientCfg, opts ...optionx.Option) HttpClient {
 		}
 	}
 
-	return httpClient
+	return httpClient, tlsErr
+}
+
+// Close releases the background resources of the client, e.g. certificate watcher, DNS cache and idle connections.
+// The client should not be used after it.
+func (httpClient *httpClient) Close() error {
+	if httpClient.tlsReloader != nil {
+		httpClient.tlsReloader.close()
+	}
+	if httpClient.dnsCache != nil {
+		httpClient.dnsCache.close()
+	}
+	if httpClient.httpTransport != nil {
+		httpClient.httpTransport.CloseIdleConnections()
+	}
+	return nil
+}
+
+// closeWhenIdle closes the client once its in-flight calls are finished.
+func (httpClient *httpClient) closeWhenIdle() {
+	<-httpClient.calls.idle()
+	httpClient.Close()
+}
+
+// callTracker counts the in-flight calls of a client.
+type callTracker struct {
+	mu      sync.Mutex
+	calls   int
+	waiters []chan struct{}
+}
+
+func (t *callTracker) begin() {
+	t.mu.Lock()
+	t.calls++
+	t.mu.Unlock()
+}
+
+func (t *callTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls--
+	if t.calls == 0 {
+		for _, waiter := range t.waiters {
+			close(waiter)
+		}
+		t.waiters = nil
+	}
+}
+
+// idle returns a channel which is closed when there is no in-flight call.
+func (t *callTracker) idle() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	waiter := make(chan struct{})
+	if t.calls == 0 {
+		close(waiter)
+	} else {
+		t.waiters = append(t.waiters, waiter)
+	}
+	return waiter
 }
 
 func (httpClient *httpClient) GET(ctx context.Context, url string, opts ...optionx.Option) (*http.Response, error) {
+	httpClient.calls.begin()
+	defer httpClient.calls.end()
 	options := optionx.NewOptions(opts...)
-	retryConfig := httpClient.getRetrySetting(ctx, httpGetMethod, url)
+	url = resolveURL(httpClient.config.BaseURL, url)
+	retryConfig := httpClient.getRetrySetting(ctx, options, httpGetMethod, url)
 	operationName := httpClient.getOpNameFromOption(url, httpGetMethod, options)
 
 	var resp *http.Response
-	req, err := http.NewRequest(httpDeleteMethod, url, nil)
+	req, err := http.NewRequest(httpGetMethod, url, nil)
 	if err != nil {
 		return resp, err
 	}
@@ -118,13 +257,32 @@ func (httpClient *httpClient) GET(ctx context.Context, url string, opts ...optio
 
 	//TODO: improvement
 	var span opentracing.Span
-	if httpClient.config.TurnOffJaeger || httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
+	if !httpClient.config.TurnOffJaeger && httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
 		span = httpClient.jaegerTracer.HttpClientTracer(ctx, req, operationName)
 		defer span.Finish()
 	}
+	if span != nil {
+		ctx = opentracing.ContextWithSpan(ctx, span)
+	}
 
-	resp, err = httpClient.firstAttemptAndRetry(ctx, &retryConfig, req, operationName, options)
+	var (
+		shared    bool
+		coalesced int
+	)
+	if httpClient.isSingleflightEnabled(options) {
+		key := buildSingleflightKey(req, httpClient.config.SingleflightSetting.Headers)
+		resp, coalesced, shared, err = httpClient.singleflight.do(ctx, key, func() (*http.Response, error) {
+			return httpClient.firstAttemptAndRetry(ctx, &retryConfig, req, operationName, options)
+		})
+		if span != nil {
+			span.SetTag("http.singleflight.shared", shared)
+			span.SetTag("http.singleflight.coalesced", coalesced)
+		}
+	} else {
+		resp, err = httpClient.firstAttemptAndRetry(ctx, &retryConfig, req, operationName, options)
+	}
 	if err != nil {
+		httpClient.recordError(ctx, span, err)
 		return resp, err
 	}
 
@@ -136,17 +294,20 @@ func (httpClient *httpClient) GET(ctx context.Context, url string, opts ...optio
 		}
 	}
 
-	logx.InfoKVf(ctx, logx.KV{"URL": url, "Status": resp.Status, "Headers": resp.Header}, "[%s] Received response", httpGetMethod)
+	logx.InfoKVf(ctx, logx.KV{"URL": url, "Status": resp.Status, "Headers": resp.Header, "Shared": shared, "Coalesced": coalesced}, "[%s] Received response", httpGetMethod)
 	return resp, nil
 }
 
 func (httpClient *httpClient) POST(ctx context.Context, url string, body io.Reader, opts ...optionx.Option) (*http.Response, error) {
+	httpClient.calls.begin()
+	defer httpClient.calls.end()
 	options := optionx.NewOptions(opts...)
-	retryConfig := httpClient.getRetrySetting(ctx, httpPostMethod, url)
+	url = resolveURL(httpClient.config.BaseURL, url)
+	retryConfig := httpClient.getRetrySetting(ctx, options, httpPostMethod, url)
 	operationName := httpClient.getOpNameFromOption(url, httpPostMethod, options)
 
 	var resp *http.Response
-	req, err := http.NewRequest(httpDeleteMethod, url, body)
+	req, err := http.NewRequest(httpPostMethod, url, body)
 	if err != nil {
 		return resp, err
 	}
@@ -156,13 +317,17 @@ func (httpClient *httpClient) POST(ctx context.Context, url string, body io.Read
 
 	//TODO: improvement
 	var span opentracing.Span
-	if httpClient.config.TurnOffJaeger || httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
+	if !httpClient.config.TurnOffJaeger && httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
 		span = httpClient.jaegerTracer.HttpClientTracer(ctx, req, operationName)
 		defer span.Finish()
 	}
+	if span != nil {
+		ctx = opentracing.ContextWithSpan(ctx, span)
+	}
 
 	resp, err = httpClient.firstAttemptAndRetry(ctx, &retryConfig, req, operationName, options)
 	if err != nil {
+		httpClient.recordError(ctx, span, err)
 		return resp, err
 	}
 
@@ -179,12 +344,15 @@ func (httpClient *httpClient) POST(ctx context.Context, url string, body io.Read
 }
 
 func (httpClient *httpClient) PUT(ctx context.Context, url string, body io.Reader, opts ...optionx.Option) (*http.Response, error) {
+	httpClient.calls.begin()
+	defer httpClient.calls.end()
 	options := optionx.NewOptions(opts...)
-	retryConfig := httpClient.getRetrySetting(ctx, httpPutMethod, url)
+	url = resolveURL(httpClient.config.BaseURL, url)
+	retryConfig := httpClient.getRetrySetting(ctx, options, httpPutMethod, url)
 	operationName := httpClient.getOpNameFromOption(url, httpPutMethod, options)
 
 	var resp *http.Response
-	req, err := http.NewRequest(httpDeleteMethod, url, body)
+	req, err := http.NewRequest(httpPutMethod, url, body)
 	if err != nil {
 		return resp, err
 	}
@@ -194,13 +362,17 @@ func (httpClient *httpClient) PUT(ctx context.Context, url string, body io.Reade
 
 	//TODO: improvement
 	var span opentracing.Span
-	if httpClient.config.TurnOffJaeger || httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
+	if !httpClient.config.TurnOffJaeger && httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
 		span = httpClient.jaegerTracer.HttpClientTracer(ctx, req, operationName)
 		defer span.Finish()
 	}
+	if span != nil {
+		ctx = opentracing.ContextWithSpan(ctx, span)
+	}
 
 	resp, err = httpClient.firstAttemptAndRetry(ctx, &retryConfig, req, operationName, options)
 	if err != nil {
+		httpClient.recordError(ctx, span, err)
 		return resp, err
 	}
 
@@ -217,12 +389,15 @@ func (httpClient *httpClient) PUT(ctx context.Context, url string, body io.Reade
 }
 
 func (httpClient *httpClient) PATCH(ctx context.Context, url string, body io.Reader, opts ...optionx.Option) (*http.Response, error) {
+	httpClient.calls.begin()
+	defer httpClient.calls.end()
 	options := optionx.NewOptions(opts...)
-	retryConfig := httpClient.getRetrySetting(ctx, httpPatchMethod, url)
+	url = resolveURL(httpClient.config.BaseURL, url)
+	retryConfig := httpClient.getRetrySetting(ctx, options, httpPatchMethod, url)
 	operationName := httpClient.getOpNameFromOption(url, httpPatchMethod, options)
 
 	var resp *http.Response
-	req, err := http.NewRequest(httpDeleteMethod, url, body)
+	req, err := http.NewRequest(httpPatchMethod, url, body)
 	if err != nil {
 		return resp, err
 	}
@@ -232,13 +407,17 @@ func (httpClient *httpClient) PATCH(ctx context.Context, url string, body io.Rea
 
 	//TODO: improvement
 	var span opentracing.Span
-	if httpClient.config.TurnOffJaeger || httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
+	if !httpClient.config.TurnOffJaeger && httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
 		span = httpClient.jaegerTracer.HttpClientTracer(ctx, req, operationName)
 		defer span.Finish()
 	}
+	if span != nil {
+		ctx = opentracing.ContextWithSpan(ctx, span)
+	}
 
 	resp, err = httpClient.firstAttemptAndRetry(ctx, &retryConfig, req, operationName, options)
 	if err != nil {
+		httpClient.recordError(ctx, span, err)
 		return resp, err
 	}
 
@@ -255,8 +434,11 @@ func (httpClient *httpClient) PATCH(ctx context.Context, url string, body io.Rea
 }
 
 func (httpClient *httpClient) DELETE(ctx context.Context, url string, body io.Reader, opts ...optionx.Option) (*http.Response, error) {
+	httpClient.calls.begin()
+	defer httpClient.calls.end()
 	options := optionx.NewOptions(opts...)
-	retryConfig := httpClient.getRetrySetting(ctx, httpDeleteMethod, url)
+	url = resolveURL(httpClient.config.BaseURL, url)
+	retryConfig := httpClient.getRetrySetting(ctx, options, httpDeleteMethod, url)
 	operationName := httpClient.getOpNameFromOption(url, httpDeleteMethod, options)
 
 	var resp *http.Response
@@ -270,13 +452,17 @@ func (httpClient *httpClient) DELETE(ctx context.Context, url string, body io.Re
 
 	//TODO: improvement
 	var span opentracing.Span
-	if httpClient.config.TurnOffJaeger || httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
+	if !httpClient.config.TurnOffJaeger && httpClient.jaegerTracer != nil && httpClient.jaegerTracer.IsEnabled() {
 		span = httpClient.jaegerTracer.HttpClientTracer(ctx, req, operationName)
 		defer span.Finish()
 	}
+	if span != nil {
+		ctx = opentracing.ContextWithSpan(ctx, span)
+	}
 
 	resp, err = httpClient.firstAttemptAndRetry(ctx, &retryConfig, req, operationName, options)
 	if err != nil {
+		httpClient.recordError(ctx, span, err)
 		return resp, err
 	}
 
@@ -302,6 +488,14 @@ func (httpClient *httpClient) setHeaderFromOption(req *http.Request, options opt
 	}
 }
 
+func (httpClient *httpClient) isSingleflightEnabled(options optionx.Options) bool {
+	enabled, ok := options.Context.Value(singleflightKey{}).(bool)
+	if !ok {
+		return httpClient.config.SingleflightSetting.Enabled
+	}
+	return enabled
+}
+
 func (httpClient *httpClient) getOpNameFromOption(url string, httpMethod string, options optionx.Options) string {
 	opName, ok := options.Context.Value(operationNameKey{}).(string)
 	if opName == "" || !ok {
@@ -310,10 +504,19 @@ func (httpClient *httpClient) getOpNameFromOption(url string, httpMethod string,
 	return opName
 }
 
-func (httpClient *httpClient) getRetrySetting(ctx context.Context, httpMethod string, url string) RetryCfg {
-	retryConfig, ok := httpClient.getAPISpecificRetrySetting(ctx, httpMethod, url)
-	if !ok {
-		retryConfig = httpClient.config.DefaultRetrySetting
+// getRetrySetting - retry setting of WithRetrySetting option has priority over API specific and default retry setting.
+func (httpClient *httpClient) getRetrySetting(ctx context.Context, options optionx.Options, httpMethod string, url string) RetryCfg {
+	var retryConfig RetryCfg
+	optionRetryConfig, ok := options.Context.Value(retrySettingKey{}).(*RetryCfg)
+	if optionRetryConfig != nil && ok {
+		retryConfig = *optionRetryConfig
+		// backoff durations are copied so that the caller's setting is not changed below
+		retryConfig.BackOffDurations = append([]time.Duration(nil), optionRetryConfig.BackOffDurations...)
+	} else {
+		retryConfig, ok = httpClient.getAPISpecificRetrySetting(ctx, httpMethod, url)
+		if !ok {
+			retryConfig = httpClient.config.DefaultRetrySetting
+		}
 	}
 
 	if uint(len(retryConfig.BackOffDurations)) < retryConfig.MaxRetryAttempts {
@@ -344,109 +547,175 @@ func (httpClient *httpClient) getAPISpecificRetrySetting(ctx context.Context, ht
 }
 
 func (httpClient *httpClient) firstAttemptAndRetry(ctx context.Context, retryConfig *RetryCfg, req *http.Request, operationName string, options optionx.Options) (*http.Response, error) {
-	var (
-		bodyReader *bytes.Reader
-		err        error
-		resp       *http.Response
-	)
-
+	// body is read once, each attempt sends its own reader because an abandoned attempt may still be reading
+	var reqData []byte
 	if req.Body != nil {
-		reqData, err := ioutil.ReadAll(req.Body)
+		var err error
+		reqData, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
 		if err != nil {
 			return nil, err
 		}
-		bodyReader = bytes.NewReader(reqData)
-		req.Body = ioutil.NopCloser(bodyReader) // prevents closing the body between retries
 	}
 
-	// without retry
-	if !retryConfig.Enabled {
-		if httpClient.config.HytrixSetting.Enabled {
-			// no retry and circuit breaker
-			return httpClient.sendHttpRequest(ctx, req, operationName, options)
+	var maxRetryAttempts uint
+	if retryConfig.Enabled {
+		maxRetryAttempts = retryConfig.MaxRetryAttempts
+	}
+	maxRetryAfter := retryConfig.MaxRetryAfter
+	if maxRetryAfter <= 0 {
+		maxRetryAfter = defaultMaxRetryAfter
+	}
+
+	for count := uint(0); ; count++ {
+		// the attempt is canceled when it is abandoned by hystrix timeout or when its response body is closed
+		attemptCtx, cancel := context.WithCancel(ctx)
+		resp, err := httpClient.attempt(attemptCtx, newAttemptRequest(attemptCtx, req, reqData), operationName, options)
+		if resp != nil && resp.Body != nil {
+			resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+		} else {
+			cancel()
+		}
+		if err == nil {
+			return resp, nil
+		}
+
+		// 5xx response is returned to the caller when it is not retried, so that the error body can be decoded.
+		// Timeout of an attempt is retried, cancellation or deadline of the caller's context is not.
+		_, serverError := err.(*ServerError)
+		if count >= maxRetryAttempts || ctx.Err() != nil || !isRetryable(err) {
+			if serverError && resp != nil {
+				return resp, nil
+			}
+			return resp, err
 		}
 
-		// no retry with circuit breaker
-		hystrix.Do(operationName,
-			func() error {
-				resp, err := httpClient.sendHttpRequest(ctx, req, operationName, options)
-				if bodyReader != nil {
-					_, _ = bodyReader.Seek(0, 0)
-				}
-
-				if err != nil {
-					return err
-				}
-
-				if resp.StatusCode >= http.StatusInternalServerError {
-					return NewServerError(req.URL.String(), resp.StatusCode)
-				}
-				return nil
-			},
-			func(err error) error {
-				return nil
-			})
+		backOffDuration := defaultBackOffDuration
+		if uint(len(retryConfig.BackOffDurations)) > count {
+			backOffDuration = retryConfig.BackOffDurations[count]
+		}
+		retryAfter, ok := errorx.RetryAfter(err)
+		if ok && retryAfter > maxRetryAfter {
+			// upstream asks to wait longer than we are willing to
+			if serverError && resp != nil {
+				return resp, nil
+			}
+			return resp, err
+		}
+		if retryAfter > backOffDuration {
+			backOffDuration = retryAfter
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		logx.WarnKVf(ctx, logx.KV{"OperationName": operationName, "Attempt": count + 1, "BackOff": backOffDuration}, "[%s] retrying request : %s", PackageName, err.Error())
+
+		timer := time.NewTimer(backOffDuration)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// recordError sets the error, its code, ID and attributes on the client span and notices it in Newrelic transaction of ctx.
+func (httpClient *httpClient) recordError(ctx context.Context, span opentracing.Span, err error) {
+	jaegerx.SetErrorTags(span, err)
+	if httpClient.config.TurnOffNewrelic || httpClient.newrelicTracer == nil || !httpClient.newrelicTracer.IsEnabled() {
+		return
+	}
+	newrelicx.NoticeError(newrelic.FromContext(ctx), err)
+}
+
+// newAttemptRequest clones the request with its own body reader and context for an attempt.
+func newAttemptRequest(ctx context.Context, req *http.Request, reqData []byte) *http.Request {
+	attemptReq := req.Clone(ctx)
+	if req.Body == nil {
+		return attemptReq
+	}
+	attemptReq.Body = ioutil.NopCloser(bytes.NewReader(reqData))
+	attemptReq.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(reqData)), nil
+	}
+	attemptReq.ContentLength = int64(len(reqData))
+	return attemptReq
+}
+
+// cancelOnCloseBody cancels the context of the attempt once the response body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (body *cancelOnCloseBody) Close() error {
+	err := body.ReadCloser.Close()
+	body.cancel()
+	return err
+}
+
+// attempt sends the request once, through circuit breaker if it is enabled. 5xx response is returned with ServerError.
+func (httpClient *httpClient) attempt(ctx context.Context, req *http.Request, operationName string, options optionx.Options) (*http.Response, error) {
+	if !httpClient.config.HytrixSetting.Enabled {
+		resp, err := httpClient.sendHttpRequest(ctx, req, operationName, options)
+		if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+			return resp, newServerErrorFromResponse(req.URL.String(), resp)
+		}
 		return resp, err
 	}
 
-	// with retry
-	for count := uint(0); count <= retryConfig.MaxRetryAttempts; count++ {
-		if httpClient.config.HytrixSetting.Enabled {
-			// retry without circuit breaker
+	// result is passed through channel because run keeps going in background when hystrix times out
+	result := make(chan *http.Response, 1)
+	err := hystrix.Do(operationName,
+		func() error {
 			resp, err := httpClient.sendHttpRequest(ctx, req, operationName, options)
-			if bodyReader != nil {
-				_, _ = bodyReader.Seek(0, 0)
+			result <- resp
+			if err != nil {
+				return err
 			}
-
-			if err == nil && resp.StatusCode >= http.StatusInternalServerError {
-				err = NewServerError(req.URL.String(), resp.StatusCode)
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return newServerErrorFromResponse(req.URL.String(), resp)
 			}
-			if err != nil {
-				backOffDuration := defaultBackOffDuration
-				if uint(len(retryConfig.BackOffDurations)) >= count {
-					backOffDuration = retryConfig.BackOffDurations[count]
-				}
-				time.Sleep(backOffDuration)
-				continue
+			return nil
+		}, nil)
+
+	if err == hystrix.ErrTimeout {
+		go func() {
+			resp := <-result
+			if resp != nil {
+				resp.Body.Close()
 			}
-		} else {
-			// retry with circuit beaker
-			hystrix.Do(operationName,
-				func() error {
-					resp, err := httpClient.sendHttpRequest(ctx, req, operationName, options)
-					if bodyReader != nil {
-						_, _ = bodyReader.Seek(0, 0)
-					}
-
-					if err != nil {
-						return err
-					}
-
-					if resp.StatusCode >= http.StatusInternalServerError {
-						return NewServerError(req.URL.String(), resp.StatusCode)
-					}
-					return nil
-				},
-				func(err error) error {
-					return nil
-				})
+		}()
+		return nil, err
+	}
 
-			if err != nil {
-				backOffDuration := defaultBackOffDuration
-				if uint(len(retryConfig.BackOffDurations)) >= count {
-					backOffDuration = retryConfig.BackOffDurations[count]
-				}
-				time.Sleep(backOffDuration)
-				continue
-			}
-		}
-		break
+	select {
+	case resp := <-result:
+		return resp, err
+	default:
+		// circuit is open or too many concurrent requests, run is not executed
+		return nil, err
 	}
-	return resp, err
+}
+
+// isRetryable - hystrix timeout is retryable, circuit open and max concurrency errors are not.
+func isRetryable(err error) bool {
+	if err == hystrix.ErrTimeout {
+		return true
+	}
+	return errorx.IsRetryable(err)
 }
 
 func (httpClient *httpClient) sendHttpRequest(ctx context.Context, req *http.Request, name string, options optionx.Options) (*http.Response, error) {
-	client := http.Client{Transport: &nethttp.Transport{}}
+	client := http.Client{Transport: &nethttp.Transport{RoundTripper: httpClient.transport}}
+	ctx = context.WithValue(ctx, requestOpNameKey{}, name)
+	if httpClient.upstream != "" {
+		ctx = context.WithValue(ctx, upstreamNameKey{}, httpClient.upstream)
+	}
+	req = req.WithContext(ctx)
 	requestTimeout, ok := options.Context.Value(httpRequestTimeoutKey{}).(time.Duration)
 	if !ok {
 		requestTimeout = defaultRequestTimeout
@@ -466,6 +735,25 @@ func (httpClient *httpClient) sendHttpRequest(ctx context.Context, req *http.Req
 	return response, err
 }
 
+func (httpClient *httpClient) UpdateFaultInjectionSetting(cfg FaultInjectionCfg) {
+	httpClient.faultInjection.update(cfg)
+	logx.Infof(context.Background(), "[%s] fault injection setting is updated, enabled : %t, rules : %d", PackageName, cfg.Enabled, len(cfg.Rules))
+}
+
+func (httpClient *httpClient) ClientCertificateExpiry() (time.Time, bool) {
+	if httpClient.tlsReloader == nil {
+		return time.Time{}, false
+	}
+	return httpClient.tlsReloader.expiry()
+}
+
+func (httpClient *httpClient) DNSCacheStats() (DNSCacheStats, bool) {
+	if httpClient.dnsCache == nil {
+		return DNSCacheStats{}, false
+	}
+	return httpClient.dnsCache.stats(), true
+}
+
 func (httpClient *httpClient) ConfigureCommand(ctx context.Context, commandName string) {
 	hytrixSetting, foundCommand := httpClient.config.HytrixSetting.CommandSetting[commandName]
 	if !foundCommand {
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/kyawmyintthein/orange-contrib/httpx/clientx/openapi"
+)
+
+/*
+	clientx-gen - generates typed API client from OpenAPI 3 document (JSON or YAML).
+	For example;
+		clientx-gen -spec ./partner-api.yaml -package partnerapi -output ./partnerapi/client_gen.go
+*/
+func main() {
+	spec := flag.String("spec", "", "path of OpenAPI 3 document (.json, .yaml or .yml)")
+	packageName := flag.String("package", "", "package name of generated code")
+	clientName := flag.String("client", "Client", "type name of generated client")
+	output := flag.String("output", "", "output file, generated code is written to stdout if empty")
+	flag.Parse()
+
+	if *spec == "" || *packageName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	doc, err := openapi.LoadDocument(*spec)
+	if err != nil {
+		exit(err)
+	}
+
+	src, err := openapi.Generate(doc, openapi.GeneratorCfg{
+		PackageName: *packageName,
+		ClientName:  *clientName,
+	})
+	if err != nil {
+		exit(err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+
+	err = ioutil.WriteFile(*output, src, 0644)
+	if err != nil {
+		exit(err)
+	}
+}
+
+func exit(err error) {
+	fmt.Fprintf(os.Stderr, "clientx-gen: %v\n", err)
+	os.Exit(1)
+}
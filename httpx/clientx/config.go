@@ -3,6 +3,7 @@ package clientx
 import "time"
 
 type HttpClientCfg struct {
+	BaseURL               string        `json:"base_url" mapstructure:"base_url"`                         // relative paths are resolved against it
 	DefaultContentType    string        `json:"default_content_type" mapstructure:"default_content_type"` // appication/json
 	DefaultRetrySetting   RetryCfg      `json:"default_retry_setting" mapstructure:"default_retry_setting"`
 	DefaultRequestTimeout time.Duration `json:"default_request_timeout" mapstructure:"default_request_timeout"` // milisecond
@@ -15,10 +16,14 @@ type HttpClientCfg struct {
 
 	APISpecificRetrySetting map[string]RetryCfg `json:"api_specific_retry_setting" mapstructure:"api_specific_retry_setting"`
 
-	HytrixSetting   HytrixCfg `json:"hytrix_setting" mapstructure:"hytrix_setting"`
-	TurnOffLogger   bool      `json:"turn_off_logger" mapstructure:"turn_off_logger"`
-	TurnOffNewrelic bool      `json:"turn_off_newrelic" mapstructure:"turn_off_newrelic"`
-	TurnOffJaeger   bool      `json:"turn_off_jaeger" mapstructure:"turn_off_jaeger"`
+	HytrixSetting         HytrixCfg         `json:"hytrix_setting" mapstructure:"hytrix_setting"`
+	SingleflightSetting   SingleflightCfg   `json:"singleflight_setting" mapstructure:"singleflight_setting"`
+	FaultInjectionSetting FaultInjectionCfg `json:"fault_injection_setting" mapstructure:"fault_injection_setting"`
+	TLSSetting            TLSCfg            `json:"tls_setting" mapstructure:"tls_setting"`
+	DNSCacheSetting       DNSCacheCfg       `json:"dns_cache_setting" mapstructure:"dns_cache_setting"`
+	TurnOffLogger         bool              `json:"turn_off_logger" mapstructure:"turn_off_logger"`
+	TurnOffNewrelic       bool              `json:"turn_off_newrelic" mapstructure:"turn_off_newrelic"`
+	TurnOffJaeger         bool              `json:"turn_off_jaeger" mapstructure:"turn_off_jaeger"`
 }
 
 type HytrixCfg struct {
@@ -36,8 +41,75 @@ type HytrixCommandCfg struct {
 	RequestVolumeThreshold int  `json:"request_volume_threshold" mapstructure:"request_volume_threshold"`
 }
 
+// SingleflightCfg - deduplicates identical concurrent GET requests.
+// Requests are identical when method, URL and the values of the listed headers are the same.
+type SingleflightCfg struct {
+	Enabled bool     `json:"enabled" mapstructure:"enabled"`
+	Headers []string `json:"headers" mapstructure:"headers"`
+}
+
+/*
+	FaultInjectionCfg - is to inject faults into outbound requests for chaos testing.
+						The rule key is either operation name provided by WithOpName or URL template with the following format:
+						"[GET]::/users/{id}": fault rule
+						When several templates match, the most specific one is used, e.g. "[GET]::/users/me" before
+						"[GET]::/users/{id}" and "[GET]::/users/{id}" before "[*]::/users/{id}".
+*/
+type FaultInjectionCfg struct {
+	Enabled bool                    `json:"enabled" mapstructure:"enabled"`
+	Rules   map[string]FaultRuleCfg `json:"rules" mapstructure:"rules"`
+}
+
+type FaultRuleCfg struct {
+	Percentage        float64       `json:"percentage" mapstructure:"percentage"` // 0 - 100
+	Latency           time.Duration `json:"latency" mapstructure:"latency"`
+	ConnectionError   bool          `json:"connection_error" mapstructure:"connection_error"`
+	StatusCode        int           `json:"status_code" mapstructure:"status_code"`
+	TruncateBody      bool          `json:"truncate_body" mapstructure:"truncate_body"`
+	TruncateBodyBytes int           `json:"truncate_body_bytes" mapstructure:"truncate_body_bytes"` // bytes kept before the body is cut
+}
+
+/*
+	TLSCfg - is to enable mutual TLS. Certificate, key and CA files are watched and reloaded when they are rotated.
+			 Server identity is verified by host name unless TrustDomain or AllowedSPIFFEIDs is set, in that case
+			 the SPIFFE ID in URI SAN of server certificate is verified. For example;
+				trust_domain: "spiffe://cluster.local"
+				allowed_spiffe_ids: ["spiffe://cluster.local/ns/payment/sa/payment-api"]
+*/
+type TLSCfg struct {
+	Enabled          bool     `json:"enabled" mapstructure:"enabled"`
+	CertFile         string   `json:"cert_file" mapstructure:"cert_file"`
+	KeyFile          string   `json:"key_file" mapstructure:"key_file"`
+	CAFile           string   `json:"ca_file" mapstructure:"ca_file"` // system pool is used if empty
+	ServerName       string   `json:"server_name" mapstructure:"server_name"`
+	TrustDomain      string   `json:"trust_domain" mapstructure:"trust_domain"`
+	AllowedSPIFFEIDs []string `json:"allowed_spiffe_ids" mapstructure:"allowed_spiffe_ids"`
+}
+
+/*
+	DNSCacheCfg - is to cache resolved addresses of upstream hosts. Go resolver does not expose record TTL,
+				  so TTL is how long the addresses are used before they are refreshed in background.
+				  Stale addresses are served for StaleTTL when the resolution fails. Overrides pin host to addresses,
+				  e.g. for testing. For example;
+					overrides:
+						user-service.internal: ["127.0.0.1"]
+*/
+type DNSCacheCfg struct {
+	Enabled       bool                `json:"enabled" mapstructure:"enabled"`
+	TTL           time.Duration       `json:"ttl" mapstructure:"ttl"`
+	StaleTTL      time.Duration       `json:"stale_ttl" mapstructure:"stale_ttl"`
+	LookupTimeout time.Duration       `json:"lookup_timeout" mapstructure:"lookup_timeout"`
+	Overrides     map[string][]string `json:"overrides" mapstructure:"overrides"`
+}
+
+/*
+	RetryCfg - errors are retried when they are classified as retryable by errorx.IsRetryable, e.g. 5xx response,
+			   timeout and connection reset. Retry-After header of 5xx response is honored up to MaxRetryAfter (default 5s),
+			   the request is not retried if the upstream asks to wait longer.
+*/
 type RetryCfg struct {
 	Enabled          bool            `json:"enabled" mapstructure:"enabled"`
-	MaxRetryAttempts uint            `json:"max_retry_attempts" json:"enabled"`
-	BackOffDurations []time.Duration `json:"back_off_durations" json:"enabled"`
+	MaxRetryAttempts uint            `json:"max_retry_attempts" mapstructure:"max_retry_attempts"`
+	BackOffDurations []time.Duration `json:"back_off_durations" mapstructure:"back_off_durations"`
+	MaxRetryAfter    time.Duration   `json:"max_retry_after" mapstructure:"max_retry_after"`
 }
@@ -0,0 +1,71 @@
+package contract
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strconv"
+)
+
+/*
+	Contract - is the set of interactions a consumer expects from a provider.
+			   Request body is kept as example so that it can be replayed, response body is kept as shape
+			   with type matchers so that provider can return any value of the expected type.
+*/
+type Contract struct {
+	Consumer     string        `json:"consumer"`
+	Provider     string        `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Description string   `json:"description"`
+	Request     Request  `json:"request"`
+	Response    Response `json:"response"`
+}
+
+type Request struct {
+	Method  string              `json:"method"`
+	Path    string              `json:"path"`
+	Query   map[string][]string `json:"query,omitempty"`
+	Headers map[string]string   `json:"headers,omitempty"`
+	Body    json.RawMessage     `json:"body,omitempty"`
+	Shape   *Matcher            `json:"shape,omitempty"`
+}
+
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Shape   *Matcher          `json:"shape,omitempty"`
+}
+
+func LoadContract(path string) (*Contract, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var contract Contract
+	err = json.Unmarshal(data, &contract)
+	if err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
+// WriteFile writes the contract as indented JSON, interactions are sorted to keep the file stable between runs.
+func (c *Contract) WriteFile(path string) error {
+	sort.SliceStable(c.Interactions, func(i, j int) bool {
+		return c.Interactions[i].key() < c.Interactions[j].key()
+	})
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func (i Interaction) key() string {
+	return i.Description + " " + i.Request.Method + " " + i.Request.Path + " " + strconv.Itoa(i.Response.Status)
+}
@@ -0,0 +1,135 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	TypeObject  string = "object"
+	TypeArray   string = "array"
+	TypeString  string = "string"
+	TypeNumber  string = "number"
+	TypeBoolean string = "boolean"
+	TypeNull    string = "null"
+)
+
+// Matcher - is type matcher of JSON value. Objects match when all expected properties exist with matching type,
+// additional properties are allowed so that provider can add fields without breaking the consumer.
+type Matcher struct {
+	Type       string              `json:"type"`
+	Properties map[string]*Matcher `json:"properties,omitempty"`
+	Items      *Matcher            `json:"items,omitempty"`
+}
+
+type Mismatch struct {
+	Interaction string `json:"interaction"`
+	Path        string `json:"path"`
+	Expected    string `json:"expected"`
+	Actual      string `json:"actual"`
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("[%s] %s : expected %s but got %s", m.Interaction, m.Path, m.Expected, m.Actual)
+}
+
+// ShapeOf builds matcher from JSON body. nil is returned for empty body.
+func ShapeOf(body []byte) (*Matcher, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+	var value interface{}
+	err := json.Unmarshal(body, &value)
+	if err != nil {
+		return nil, err
+	}
+	return shapeOfValue(value), nil
+}
+
+func shapeOfValue(value interface{}) *Matcher {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		matcher := &Matcher{Type: TypeObject, Properties: make(map[string]*Matcher, len(v))}
+		for key, val := range v {
+			matcher.Properties[key] = shapeOfValue(val)
+		}
+		return matcher
+	case []interface{}:
+		matcher := &Matcher{Type: TypeArray}
+		if len(v) > 0 {
+			matcher.Items = shapeOfValue(v[0])
+		}
+		return matcher
+	default:
+		return &Matcher{Type: typeOf(v)}
+	}
+}
+
+// Match compares JSON body against the matcher and returns mismatches.
+func (m *Matcher) Match(interaction string, body []byte) []Mismatch {
+	if m == nil {
+		return nil
+	}
+	if len(body) == 0 {
+		return []Mismatch{{Interaction: interaction, Path: "$", Expected: m.Type, Actual: "empty body"}}
+	}
+
+	var value interface{}
+	err := json.Unmarshal(body, &value)
+	if err != nil {
+		return []Mismatch{{Interaction: interaction, Path: "$", Expected: m.Type, Actual: "invalid JSON"}}
+	}
+	return m.match(interaction, "$", value)
+}
+
+func (m *Matcher) match(interaction string, path string, value interface{}) []Mismatch {
+	actualType := typeOf(value)
+	if actualType != m.Type {
+		return []Mismatch{{Interaction: interaction, Path: path, Expected: m.Type, Actual: actualType}}
+	}
+
+	var mismatches []Mismatch
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(m.Properties))
+		for key := range m.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			propertyPath := path + "." + key
+			val, ok := v[key]
+			if !ok {
+				mismatches = append(mismatches, Mismatch{Interaction: interaction, Path: propertyPath, Expected: m.Properties[key].Type, Actual: "missing"})
+				continue
+			}
+			mismatches = append(mismatches, m.Properties[key].match(interaction, propertyPath, val)...)
+		}
+	case []interface{}:
+		if m.Items == nil {
+			break
+		}
+		for i, item := range v {
+			mismatches = append(mismatches, m.Items.match(interaction, fmt.Sprintf("%s[%d]", path, i), item)...)
+		}
+	}
+	return mismatches
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return TypeObject
+	case []interface{}:
+		return TypeArray
+	case string:
+		return TypeString
+	case float64, json.Number:
+		return TypeNumber
+	case bool:
+		return TypeBoolean
+	default:
+		return TypeNull
+	}
+}
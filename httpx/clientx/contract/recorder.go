@@ -0,0 +1,99 @@
+package contract
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+type RecorderCfg struct {
+	Consumer string   `json:"consumer" mapstructure:"consumer"`
+	Provider string   `json:"provider" mapstructure:"provider"`
+	Headers  []string `json:"headers" mapstructure:"headers"` // request and response headers to be kept in the contract
+}
+
+/*
+	Recorder - captures the interactions performed through clientx and writes them as contract.
+			   It implements clientx.InteractionRecorder and is given to the client with clientx.WithContractRecorder.
+*/
+type Recorder struct {
+	cfg *RecorderCfg
+
+	mu           sync.Mutex
+	interactions map[string]Interaction
+}
+
+func NewRecorder(cfg *RecorderCfg) *Recorder {
+	return &Recorder{
+		cfg:          cfg,
+		interactions: make(map[string]Interaction),
+	}
+}
+
+// RecordInteraction keeps the latest interaction of each operation, method, path and status.
+func (r *Recorder) RecordInteraction(opName string, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) {
+	interaction := Interaction{
+		Description: opName,
+		Request: Request{
+			Method:  req.Method,
+			Path:    req.URL.Path,
+			Headers: r.selectHeaders(req.Header),
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: r.selectHeaders(resp.Header),
+		},
+	}
+
+	if len(req.URL.RawQuery) > 0 {
+		interaction.Request.Query = req.URL.Query()
+	}
+
+	if shape, err := ShapeOf(reqBody); err == nil && shape != nil {
+		interaction.Request.Body = json.RawMessage(reqBody)
+		interaction.Request.Shape = shape
+	}
+
+	if shape, err := ShapeOf(respBody); err == nil {
+		interaction.Response.Shape = shape
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions[interaction.key()] = interaction
+}
+
+func (r *Recorder) Contract() *Contract {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	contract := &Contract{
+		Consumer:     r.cfg.Consumer,
+		Provider:     r.cfg.Provider,
+		Interactions: make([]Interaction, 0, len(r.interactions)),
+	}
+	for _, interaction := range r.interactions {
+		contract.Interactions = append(contract.Interactions, interaction)
+	}
+	return contract
+}
+
+func (r *Recorder) WriteFile(path string) error {
+	return r.Contract().WriteFile(path)
+}
+
+func (r *Recorder) selectHeaders(header http.Header) map[string]string {
+	if len(r.cfg.Headers) == 0 {
+		return nil
+	}
+	selected := make(map[string]string)
+	for _, key := range r.cfg.Headers {
+		if value := header.Get(key); value != "" {
+			selected[http.CanonicalHeaderKey(key)] = value
+		}
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+	return selected
+}
@@ -0,0 +1,106 @@
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+)
+
+type VerificationResult struct {
+	Contract   *Contract
+	Verified   int
+	Mismatches []Mismatch
+}
+
+func (result *VerificationResult) Passed() bool {
+	return len(result.Mismatches) == 0
+}
+
+// Err returns nil if all the interactions are verified, otherwise the error lists every mismatch.
+func (result *VerificationResult) Err() error {
+	if result.Passed() {
+		return nil
+	}
+	messages := make([]string, 0, len(result.Mismatches))
+	for _, mismatch := range result.Mismatches {
+		messages = append(messages, mismatch.String())
+	}
+	return errorx.NewErrorX("contract between '%s' and '%s' is broken:\n%s",
+		result.Contract.Consumer, result.Contract.Provider, strings.Join(messages, "\n"))
+}
+
+/*
+	Verify - replays every interaction of the contract against provider's handler in memory and compares
+			 status code, headers and response body shape. No network is used so that it can run in CI.
+*/
+func Verify(contract *Contract, handler http.Handler) *VerificationResult {
+	result := &VerificationResult{Contract: contract}
+	for _, interaction := range contract.Interactions {
+		mismatches := verifyInteraction(interaction, handler)
+		if len(mismatches) == 0 {
+			result.Verified++
+		}
+		result.Mismatches = append(result.Mismatches, mismatches...)
+	}
+	return result
+}
+
+func VerifyFile(path string, handler http.Handler) (*VerificationResult, error) {
+	contract, err := LoadContract(path)
+	if err != nil {
+		return nil, err
+	}
+	return Verify(contract, handler), nil
+}
+
+func verifyInteraction(interaction Interaction, handler http.Handler) []Mismatch {
+	target := interaction.Request.Path
+	if len(interaction.Request.Query) > 0 {
+		target += "?" + url.Values(interaction.Request.Query).Encode()
+	}
+
+	req := httptest.NewRequest(interaction.Request.Method, target, bytes.NewReader(interaction.Request.Body))
+	for key, value := range interaction.Request.Headers {
+		req.Header.Set(key, value)
+	}
+	if len(interaction.Request.Body) > 0 && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	name := interaction.Description
+	if name == "" {
+		name = fmt.Sprintf("%s %s", interaction.Request.Method, interaction.Request.Path)
+	}
+
+	if recorder.Code != interaction.Response.Status {
+		return []Mismatch{{
+			Interaction: name,
+			Path:        "status",
+			Expected:    strconv.Itoa(interaction.Response.Status),
+			Actual:      strconv.Itoa(recorder.Code),
+		}}
+	}
+
+	var mismatches []Mismatch
+	for key, expected := range interaction.Response.Headers {
+		actual := recorder.Header().Get(key)
+		if !strings.EqualFold(mediaType(actual), mediaType(expected)) {
+			mismatches = append(mismatches, Mismatch{Interaction: name, Path: "header." + key, Expected: expected, Actual: actual})
+		}
+	}
+	return append(mismatches, interaction.Response.Shape.Match(name, recorder.Body.Bytes())...)
+}
+
+// mediaType drops the parameters of header value, e.g. "application/json; charset=utf-8"
+func mediaType(value string) string {
+	return strings.TrimSpace(strings.Split(value, ";")[0])
+}
@@ -0,0 +1,195 @@
+package clientx
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kyawmyintthein/orange-contrib/logx"
+)
+
+const (
+	defaultDNSCacheTTL      time.Duration = 30 * time.Second
+	defaultDNSCacheStaleTTL time.Duration = 10 * time.Minute
+	defaultDNSLookupTimeout time.Duration = 2 * time.Second
+	defaultDialTimeout      time.Duration = 30 * time.Second
+	defaultDialKeepAlive    time.Duration = 30 * time.Second
+)
+
+type DNSCacheInspector interface {
+	// DNSCacheStats returns the statistics of DNS cache and false if DNS cache is not enabled.
+	DNSCacheStats() (DNSCacheStats, bool)
+}
+
+type DNSCacheStats struct {
+	Entries   int    `json:"entries"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	StaleHits uint64 `json:"stale_hits"`
+	Refreshes uint64 `json:"refreshes"`
+	Failures  uint64 `json:"failures"`
+}
+
+type dnsCacheEntry struct {
+	addrs      []string
+	resolvedAt time.Time
+	refreshing bool
+}
+
+// cachingResolver - resolves host names for the dialer of the transport and keeps the addresses in memory.
+// Expired entry is returned right away while it is refreshed in background (stale-while-revalidate).
+type cachingResolver struct {
+	// counters are placed first to keep 64-bit alignment for atomic operations on 32-bit platforms
+	hits      uint64
+	misses    uint64
+	staleHits uint64
+	refreshes uint64
+	failures  uint64
+
+	cfg      *DNSCacheCfg
+	resolver *net.Resolver
+	dialer   *net.Dialer
+
+	mu      sync.Mutex
+	entries map[string]*dnsCacheEntry
+}
+
+func newCachingResolver(cfg *DNSCacheCfg) *cachingResolver {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultDNSCacheTTL
+	}
+	if cfg.StaleTTL <= 0 {
+		cfg.StaleTTL = defaultDNSCacheStaleTTL
+	}
+	if cfg.LookupTimeout <= 0 {
+		cfg.LookupTimeout = defaultDNSLookupTimeout
+	}
+
+	return &cachingResolver{
+		cfg:      cfg,
+		resolver: net.DefaultResolver,
+		dialer: &net.Dialer{
+			Timeout:   defaultDialTimeout,
+			KeepAlive: defaultDialKeepAlive,
+		},
+		entries: make(map[string]*dnsCacheEntry),
+	}
+}
+
+// dialContext tries the resolved addresses in order until one of them accepts the connection.
+func (r *cachingResolver) dialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		return r.dialer.DialContext(ctx, network, address)
+	}
+
+	addrs, err := r.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn net.Conn
+	for _, addr := range addrs {
+		conn, err = r.dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+func (r *cachingResolver) lookup(ctx context.Context, host string) ([]string, error) {
+	host = strings.ToLower(host)
+	if addrs, ok := r.cfg.Overrides[host]; ok && len(addrs) > 0 {
+		atomic.AddUint64(&r.hits, 1)
+		return addrs, nil
+	}
+
+	now := time.Now()
+	r.mu.Lock()
+	entry, ok := r.entries[host]
+	if ok && now.Sub(entry.resolvedAt) < r.cfg.TTL {
+		r.mu.Unlock()
+		atomic.AddUint64(&r.hits, 1)
+		return entry.addrs, nil
+	}
+	if ok && now.Sub(entry.resolvedAt) < r.cfg.StaleTTL {
+		if !entry.refreshing {
+			entry.refreshing = true
+			go r.refresh(host)
+		}
+		r.mu.Unlock()
+		atomic.AddUint64(&r.staleHits, 1)
+		return entry.addrs, nil
+	}
+	r.mu.Unlock()
+
+	atomic.AddUint64(&r.misses, 1)
+	return r.resolve(ctx, host)
+}
+
+func (r *cachingResolver) refresh(host string) {
+	atomic.AddUint64(&r.refreshes, 1)
+	_, err := r.resolve(context.Background(), host)
+	if err != nil {
+		logx.Warnf(context.Background(), "[%s] failed to refresh DNS cache of '%s', keep serving stale addresses : %s", PackageName, host, err.Error())
+	}
+}
+
+// resolve looks up the host and stores the addresses. Entry is kept as it is when the lookup fails.
+func (r *cachingResolver) resolve(ctx context.Context, host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.LookupTimeout)
+	defer cancel()
+	ipAddrs, err := r.resolver.LookupIPAddr(ctx, host)
+	if err == nil && len(ipAddrs) == 0 {
+		err = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[host]
+	if err != nil {
+		atomic.AddUint64(&r.failures, 1)
+		if ok {
+			entry.refreshing = false
+		}
+		return nil, err
+	}
+
+	addrs := make([]string, 0, len(ipAddrs))
+	for _, ipAddr := range ipAddrs {
+		addrs = append(addrs, ipAddr.String())
+	}
+	r.entries[host] = &dnsCacheEntry{
+		addrs:      addrs,
+		resolvedAt: time.Now(),
+	}
+	return addrs, nil
+}
+
+// close drops the cached addresses.
+func (r *cachingResolver) close() {
+	r.mu.Lock()
+	r.entries = make(map[string]*dnsCacheEntry)
+	r.mu.Unlock()
+}
+
+func (r *cachingResolver) stats() DNSCacheStats {
+	r.mu.Lock()
+	entries := len(r.entries)
+	r.mu.Unlock()
+	return DNSCacheStats{
+		Entries:   entries,
+		Hits:      atomic.LoadUint64(&r.hits),
+		Misses:    atomic.LoadUint64(&r.misses),
+		StaleHits: atomic.LoadUint64(&r.staleHits),
+		Refreshes: atomic.LoadUint64(&r.refreshes),
+		Failures:  atomic.LoadUint64(&r.failures),
+	}
+}
@@ -1,13 +1,194 @@
 package clientx
 
-import "github.com/kyawmyintthein/orange-contrib/errorx"
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+)
 
 type ServerError struct {
 	*errorx.ErrorX
+	*errorx.ErrorWithHttpStatus
+	retryAfter time.Duration
 }
 
 func NewServerError(url string, statusCode int) *ServerError {
 	return &ServerError{
-		errorx.NewErrorX("server return 5xx status code : %d from URL: %s", statusCode, url),
+		ErrorX:              errorx.NewErrorX("server return 5xx status code : %d from URL: %s", statusCode, url),
+		ErrorWithHttpStatus: errorx.NewErrorWithHttpStatus(statusCode),
+	}
+}
+
+// newServerErrorFromResponse keeps Retry-After header of the response as retry hint.
+func newServerErrorFromResponse(url string, resp *http.Response) *ServerError {
+	err := NewServerError(url, resp.StatusCode)
+	err.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	return err
+}
+
+// Retryable - 501 Not Implemented and 505 HTTP Version Not Supported are not going to change by retrying.
+func (err *ServerError) Retryable() bool {
+	switch err.StatusCode() {
+	case http.StatusNotImplemented, http.StatusHTTPVersionNotSupported:
+		return false
+	}
+	return true
+}
+
+func (err *ServerError) RetryAfter() time.Duration {
+	return err.retryAfter
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(value)
+	if err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	at, err := http.ParseTime(value)
+	if err == nil && time.Until(at) > 0 {
+		return time.Until(at)
+	}
+	return 0
+}
+
+type FaultInjectedError struct {
+	*errorx.ErrorX
+}
+
+func NewFaultInjectedError(rule string, url string) *FaultInjectedError {
+	return &FaultInjectedError{
+		errorx.NewErrorX("injected connection error by fault rule '%s' for URL: %s", rule, url),
 	}
 }
+
+type InvalidCertificateError struct {
+	*errorx.ErrorX
+}
+
+func NewInvalidCertificateError(file string) *InvalidCertificateError {
+	return &InvalidCertificateError{
+		errorx.NewErrorX("no valid PEM certificate found in file: %s", file),
+	}
+}
+
+type InvalidServerCertificateError struct {
+	*errorx.ErrorX
+}
+
+func NewInvalidServerCertificateError(serverName string, reason string) *InvalidServerCertificateError {
+	return &InvalidServerCertificateError{
+		errorx.NewErrorX("invalid certificate from server '%s' : %s", serverName, reason),
+	}
+}
+
+type UpstreamNotFoundError struct {
+	*errorx.ErrorX
+}
+
+func NewUpstreamNotFoundError(name string) *UpstreamNotFoundError {
+	return &UpstreamNotFoundError{
+		errorx.NewErrorX("upstream '%s' is not found in registry", name),
+	}
+}
+
+type InvalidUpstreamError struct {
+	*errorx.ErrorX
+}
+
+func NewInvalidUpstreamError(name string, reason string) *InvalidUpstreamError {
+	return &InvalidUpstreamError{
+		errorx.NewErrorX("invalid setting of upstream '%s' : %s", name, reason),
+	}
+}
+
+// UnexpectedStatusError - error code is reverse mapped from the status by errorx.ErrorCodeFromHttpStatus.
+type UnexpectedStatusError struct {
+	*errorx.ErrorX
+	*errorx.ErrorWithCode
+	*errorx.ErrorWithHttpStatus
+	body []byte
+}
+
+func NewUnexpectedStatusError(url string, statusCode int, body []byte) *UnexpectedStatusError {
+	return &UnexpectedStatusError{
+		errorx.NewErrorX("unexpected status code : %d from URL: %s", statusCode, url),
+		errorx.NewErrorWithCode(errorx.ErrorCodeFromHttpStatus(statusCode)),
+		errorx.NewErrorWithHttpStatus(statusCode),
+		body,
+	}
+}
+
+func (err *UnexpectedStatusError) Body() []byte {
+	return err.body
+}
+
+type BatchError struct {
+	*errorx.ErrorX
+	Failures map[int]error // index of the request in the batch
+}
+
+func NewBatchError(total int, failures map[int]error) *BatchError {
+	return &BatchError{
+		ErrorX:   errorx.NewErrorX("%d of %d batch requests failed", len(failures), total),
+		Failures: failures,
+	}
+}
+
+type UnsupportedMethodError struct {
+	*errorx.ErrorX
+}
+
+func NewUnsupportedMethodError(method string) *UnsupportedMethodError {
+	return &UnsupportedMethodError{
+		errorx.NewErrorX("unsupported http method '%s'", method),
+	}
+}
+
+/*
+	RemoteError - is the error returned by an upstream in errorx wire format. Error code, ID and http status
+				  of the upstream error are kept, so the error can be handled in the same way as a local error.
+*/
+type RemoteError struct {
+	*errorx.ErrorX
+	*errorx.ErrorWithCode
+	*errorx.ErrorWithID
+	*errorx.ErrorWithHttpStatus
+	*errorx.ErrorWithAttributes
+	upstream string
+}
+
+func NewRemoteError(upstream string, wireErr *errorx.WireError) *RemoteError {
+	err := &RemoteError{
+		errorx.NewErrorX("%s", wireErr.Message),
+		errorx.NewErrorWithCode(wireErr.Code),
+		errorx.NewErrorWithID(wireErr.ID),
+		errorx.NewErrorWithHttpStatus(wireErr.Status),
+		errorx.NewErrorWithAttributes(errorx.Attr("upstream", upstream)),
+		upstream,
+	}
+	for key, value := range wireErr.Attributes {
+		err.AddAttributes(errorx.Attr(key, value))
+	}
+	if wireErr.Cause != nil {
+		err.Wrap(NewRemoteError(upstream, wireErr.Cause))
+	}
+	return err
+}
+
+func (err *RemoteError) Is(target error) bool {
+	return errorx.Match(err, target)
+}
+
+func (err *RemoteError) Upstream() string {
+	return err.upstream
+}
+
+func (err *RemoteError) Error() string {
+	return fmt.Sprintf("[%s] %s", err.upstream, err.ErrorX.Error())
+}
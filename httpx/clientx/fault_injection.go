@@ -0,0 +1,266 @@
+package clientx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyawmyintthein/orange-contrib/logx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	faultLatency         string = "latency"
+	faultConnectionError string = "connection_error"
+	faultStatusCode      string = "status_code"
+	faultTruncatedBody   string = "truncated_body"
+)
+
+const (
+	faultInjectedHeader string = "X-Fault-Injected"
+)
+
+type FaultInjector interface {
+	UpdateFaultInjectionSetting(FaultInjectionCfg)
+}
+
+type requestOpNameKey struct{}
+
+// faultInjectionTransport - is http.RoundTripper which injects faults configured in FaultInjectionCfg before
+// (or instead of) passing the request to the underlying transport.
+type faultInjectionTransport struct {
+	next http.RoundTripper
+
+	mu     sync.RWMutex
+	cfg    FaultInjectionCfg
+	random *rand.Rand
+}
+
+func newFaultInjectionTransport(next http.RoundTripper, cfg FaultInjectionCfg) *faultInjectionTransport {
+	return &faultInjectionTransport{
+		next:   next,
+		cfg:    cfg,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (t *faultInjectionTransport) update(cfg FaultInjectionCfg) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cfg = cfg
+}
+
+func (t *faultInjectionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	opName, _ := ctx.Value(requestOpNameKey{}).(string)
+
+	ruleName, rule, ok := t.matchRule(opName, req)
+	if !ok || !t.shouldInject(rule.Percentage) {
+		return t.next.RoundTrip(req)
+	}
+
+	var injected []string
+	if rule.Latency > 0 {
+		injected = append(injected, faultLatency)
+		select {
+		case <-time.After(rule.Latency):
+		case <-ctx.Done():
+			t.report(ctx, ruleName, req, injected)
+			return nil, ctx.Err()
+		}
+	}
+
+	if rule.ConnectionError {
+		injected = append(injected, faultConnectionError)
+		t.report(ctx, ruleName, req, injected)
+		return nil, NewFaultInjectedError(ruleName, req.URL.String())
+	}
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	if rule.StatusCode != 0 {
+		injected = append(injected, faultStatusCode)
+		resp = &http.Response{
+			Status:        fmt.Sprintf("%d %s", rule.StatusCode, http.StatusText(rule.StatusCode)),
+			StatusCode:    rule.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        make(http.Header),
+			Body:          ioutil.NopCloser(bytes.NewReader(nil)),
+			ContentLength: 0,
+			Request:       req,
+		}
+	} else {
+		resp, err = t.next.RoundTrip(req)
+		if err != nil {
+			t.report(ctx, ruleName, req, injected)
+			return resp, err
+		}
+	}
+
+	if rule.TruncateBody {
+		injected = append(injected, faultTruncatedBody)
+		resp.Body = &truncatedBody{
+			body:      resp.Body,
+			remaining: int64(rule.TruncateBodyBytes),
+		}
+		resp.ContentLength = -1
+	}
+
+	if len(injected) > 0 {
+		resp.Header.Set(faultInjectedHeader, strings.Join(injected, ","))
+	}
+	t.report(ctx, ruleName, req, injected)
+	return resp, nil
+}
+
+func (t *faultInjectionTransport) matchRule(opName string, req *http.Request) (string, FaultRuleCfg, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if !t.cfg.Enabled || len(t.cfg.Rules) == 0 {
+		return "", FaultRuleCfg{}, false
+	}
+
+	if rule, ok := t.cfg.Rules[opName]; ok && opName != "" {
+		return opName, rule, true
+	}
+
+	// the most specific template wins when several templates match, so that the injected fault does not depend on map order
+	var matched []string
+	for template := range t.cfg.Rules {
+		if matchURLTemplate(template, req.Method, req.URL.Path) {
+			matched = append(matched, template)
+		}
+	}
+	if len(matched) == 0 {
+		return "", FaultRuleCfg{}, false
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return moreSpecificTemplate(matched[i], matched[j])
+	})
+	return matched[0], t.cfg.Rules[matched[0]], true
+}
+
+func (t *faultInjectionTransport) shouldInject(percentage float64) bool {
+	if percentage <= 0 {
+		return false
+	}
+	if percentage >= 100 {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.random.Float64()*100 < percentage
+}
+
+func (t *faultInjectionTransport) report(ctx context.Context, ruleName string, req *http.Request, injected []string) {
+	if len(injected) == 0 {
+		return
+	}
+
+	faults := strings.Join(injected, ",")
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		span.SetTag("fault.injected", true)
+		span.SetTag("fault.rule", ruleName)
+		span.SetTag("fault.types", faults)
+		if strings.Contains(faults, faultConnectionError) {
+			ext.Error.Set(span, true)
+		}
+	}
+	logx.WarnKVf(ctx, logx.KV{"URL": req.URL.String(), "Rule": ruleName, "Faults": faults}, "[%s] Injected fault", PackageName)
+}
+
+// matchURLTemplate - matches the request against URL template of format "[GET]::/users/{id}".
+// Method "*" matches any method and segment "{name}" matches any single path segment.
+func matchURLTemplate(template string, method string, path string) bool {
+	parts := strings.SplitN(template, "::", 2)
+	if len(parts) != 2 {
+		return false
+	}
+
+	templateMethod := strings.Trim(parts[0], "[]")
+	if templateMethod != "*" && !strings.EqualFold(templateMethod, method) {
+		return false
+	}
+
+	templateSegments := strings.Split(strings.Trim(parts[1], "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(templateSegments) != len(pathSegments) {
+		return false
+	}
+
+	for i, segment := range templateSegments {
+		if isPathParam(segment) {
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+	moreSpecificTemplate - compares two templates which match the same request. At the first segment where they differ,
+						   a literal segment is more specific than a path parameter. Then an exact method is more
+						   specific than "*", and the templates are ordered by name at last.
+*/
+func moreSpecificTemplate(a string, b string) bool {
+	aParts := strings.SplitN(a, "::", 2)
+	bParts := strings.SplitN(b, "::", 2)
+	aSegments := strings.Split(strings.Trim(aParts[1], "/"), "/")
+	bSegments := strings.Split(strings.Trim(bParts[1], "/"), "/")
+	for i := 0; i < len(aSegments) && i < len(bSegments); i++ {
+		aParam := isPathParam(aSegments[i])
+		bParam := isPathParam(bSegments[i])
+		if aParam != bParam {
+			return bParam
+		}
+	}
+
+	aAnyMethod := strings.Trim(aParts[0], "[]") == "*"
+	bAnyMethod := strings.Trim(bParts[0], "[]") == "*"
+	if aAnyMethod != bAnyMethod {
+		return bAnyMethod
+	}
+	return a < b
+}
+
+func isPathParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// truncatedBody returns at most remaining bytes and then fails as a connection dropped in the middle of the body.
+type truncatedBody struct {
+	body      io.ReadCloser
+	remaining int64
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *truncatedBody) Close() error {
+	return b.body.Close()
+}
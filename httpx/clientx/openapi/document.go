@@ -0,0 +1,230 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+	"gopkg.in/yaml.v2"
+)
+
+// Document is the subset of OpenAPI 3 document used by the generator.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
+
+type PathItem struct {
+	Get        *Operation   `json:"get"`
+	Put        *Operation   `json:"put"`
+	Post       *Operation   `json:"post"`
+	Delete     *Operation   `json:"delete"`
+	Patch      *Operation   `json:"patch"`
+	Parameters []*Parameter `json:"parameters"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description"`
+	Deprecated  bool                 `json:"deprecated"`
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Ref         string                `json:"$ref"`
+	Description string                `json:"description"`
+	Required    bool                  `json:"required"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref"`
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Nullable             bool               `json:"nullable"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"` // either boolean or schema
+	Enum                 []interface{}      `json:"enum"`
+	AllOf                []*Schema          `json:"allOf"`
+}
+
+type Components struct {
+	Schemas       map[string]*Schema      `json:"schemas"`
+	Parameters    map[string]*Parameter   `json:"parameters"`
+	RequestBodies map[string]*RequestBody `json:"requestBodies"`
+	Responses     map[string]*Response    `json:"responses"`
+}
+
+// LoadDocument reads OpenAPI document from JSON or YAML file, the format is decided by file extension.
+func LoadDocument(path string) (*Document, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	default:
+		return ParseJSON(data)
+	}
+}
+
+func ParseJSON(data []byte) (*Document, error) {
+	var doc Document
+	err := json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, doc.validate()
+}
+
+// ParseYAML converts YAML document into JSON first so that the same struct tags are used for both formats.
+func ParseYAML(data []byte) (*Document, error) {
+	var raw interface{}
+	err := yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(convertYAMLValue(raw))
+	if err != nil {
+		return nil, err
+	}
+	return ParseJSON(jsonData)
+}
+
+func (doc *Document) validate() error {
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return errorx.NewErrorX("unsupported OpenAPI version '%s', only 3.x is supported", doc.OpenAPI)
+	}
+	return nil
+}
+
+func (doc *Document) resolveSchema(schema *Schema) (string, *Schema, error) {
+	if schema == nil || schema.Ref == "" {
+		return "", schema, nil
+	}
+	name, err := refName(schema.Ref, "#/components/schemas/")
+	if err != nil {
+		return "", nil, err
+	}
+	resolved, ok := doc.Components.Schemas[name]
+	if !ok {
+		return "", nil, errorx.NewErrorX("schema '%s' is not found", schema.Ref)
+	}
+	return name, resolved, nil
+}
+
+func (doc *Document) resolveParameter(param *Parameter) (*Parameter, error) {
+	if param.Ref == "" {
+		return param, nil
+	}
+	name, err := refName(param.Ref, "#/components/parameters/")
+	if err != nil {
+		return nil, err
+	}
+	resolved, ok := doc.Components.Parameters[name]
+	if !ok {
+		return nil, errorx.NewErrorX("parameter '%s' is not found", param.Ref)
+	}
+	return resolved, nil
+}
+
+func (doc *Document) resolveRequestBody(body *RequestBody) (*RequestBody, error) {
+	if body == nil || body.Ref == "" {
+		return body, nil
+	}
+	name, err := refName(body.Ref, "#/components/requestBodies/")
+	if err != nil {
+		return nil, err
+	}
+	resolved, ok := doc.Components.RequestBodies[name]
+	if !ok {
+		return nil, errorx.NewErrorX("request body '%s' is not found", body.Ref)
+	}
+	return resolved, nil
+}
+
+func (doc *Document) resolveResponse(resp *Response) (*Response, error) {
+	if resp == nil || resp.Ref == "" {
+		return resp, nil
+	}
+	name, err := refName(resp.Ref, "#/components/responses/")
+	if err != nil {
+		return nil, err
+	}
+	resolved, ok := doc.Components.Responses[name]
+	if !ok {
+		return nil, errorx.NewErrorX("response '%s' is not found", resp.Ref)
+	}
+	return resolved, nil
+}
+
+func refName(ref string, prefix string) (string, error) {
+	if !strings.HasPrefix(ref, prefix) {
+		return "", errorx.NewErrorX("unsupported reference '%s', only local references of '%s' are supported", ref, prefix)
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
+
+// convertYAMLValue converts map[interface{}]interface{} produced by yaml.v2 into map[string]interface{}.
+func convertYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = convertYAMLValue(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = convertYAMLValue(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
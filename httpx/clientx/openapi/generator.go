@@ -0,0 +1,809 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+)
+
+const (
+	applicationJSON string = "application/json"
+	clientxImport   string = "github.com/kyawmyintthein/orange-contrib/httpx/clientx"
+	optionxImport   string = "github.com/kyawmyintthein/orange-contrib/optionx"
+)
+
+var (
+	// methods are generated in this order and mapped to the method of clientx.HttpClient
+	httpMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+	pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+type GeneratorCfg struct {
+	PackageName string
+	ClientName  string // default is "Client"
+}
+
+/*
+	Generate - emits Go source of typed models and API client built on clientx.HttpClient.
+			   operationId of each operation is used as method name and as operation name of WithOpName,
+			   so that tracing and per-API settings of clientx line up with the API document. An error is
+			   returned when two names of the document map to the same Go identifier, e.g. "pet_id" and "petId".
+*/
+func Generate(doc *Document, cfg GeneratorCfg) ([]byte, error) {
+	if cfg.PackageName == "" {
+		return nil, errorx.NewErrorX("package name is required")
+	}
+	if cfg.ClientName == "" {
+		cfg.ClientName = "Client"
+	}
+
+	g := &generator{
+		doc:      doc,
+		cfg:      cfg,
+		imports:  make(map[string]bool),
+		declared: make(map[string]string),
+		methods:  make(map[string]string),
+	}
+
+	// component schemas are queued first so that they are generated before inline types of operations
+	err := g.queueComponents()
+	if err != nil {
+		return nil, err
+	}
+	err = g.generateOperations()
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.generateModels()
+	if err != nil {
+		return nil, err
+	}
+
+	src := g.source()
+	formatted, err := format.Source(src)
+	if err != nil {
+		// return unformatted source as well so that the problem can be inspected
+		return src, errorx.NewErrorX("failed to format generated source : %v", err)
+	}
+	return formatted, nil
+}
+
+type generator struct {
+	doc *Document
+	cfg GeneratorCfg
+
+	imports map[string]bool
+	// declared maps top level identifiers and methods to their origin in the document
+	declared map[string]string
+	methods  map[string]string
+	pending  []namedSchema
+
+	models     bytes.Buffer
+	operations bytes.Buffer
+}
+
+type namedSchema struct {
+	name   string
+	schema *Schema
+}
+
+type typeInfo struct {
+	expr string
+	// reference types (slice, map and interface) are not used with pointer
+	reference bool
+}
+
+type parameterInfo struct {
+	param     *Parameter
+	fieldName string
+	varName   string
+	typ       typeInfo
+}
+
+func (g *generator) source() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by clientx-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "// Package %s is a client of %s %s.\n", g.cfg.PackageName, g.doc.Info.Title, g.doc.Info.Version)
+	fmt.Fprintf(&buf, "package %s\n\n", g.cfg.PackageName)
+
+	// standard library imports are grouped before the others
+	var stdImports, otherImports []string
+	for imp := range g.imports {
+		if strings.Contains(strings.Split(imp, "/")[0], ".") {
+			otherImports = append(otherImports, imp)
+		} else {
+			stdImports = append(stdImports, imp)
+		}
+	}
+	sort.Strings(stdImports)
+	sort.Strings(otherImports)
+
+	buf.WriteString("import (\n")
+	for _, imp := range stdImports {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	buf.WriteString("\n")
+	for _, imp := range otherImports {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.Write(g.models.Bytes())
+	buf.Write(g.operations.Bytes())
+	return buf.Bytes()
+}
+
+func (g *generator) queueComponents() error {
+	names := make([]string, 0, len(g.doc.Components.Schemas))
+	for name := range g.doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		err := g.queue(exportedName(name), fmt.Sprintf("component schema '%s'", name), g.doc.Components.Schemas[name])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) generateModels() error {
+	for len(g.pending) > 0 {
+		next := g.pending[0]
+		g.pending = g.pending[1:]
+		err := g.generateModel(next.name, next.schema)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) queue(name string, origin string, schema *Schema) error {
+	err := g.declare(name, origin)
+	if err != nil {
+		return err
+	}
+	g.pending = append(g.pending, namedSchema{name: name, schema: schema})
+	return nil
+}
+
+// declare reserves a top level identifier, a type of one schema must not replace the type of another.
+func (g *generator) declare(name string, origin string) error {
+	if previous, ok := g.declared[name]; ok {
+		return errorx.NewErrorX("name '%s' of %s collides with %s", name, origin, previous)
+	}
+	g.declared[name] = origin
+	return nil
+}
+
+// checkUniqueNames returns an error if two names map to the same Go identifier within a struct or a signature.
+func checkUniqueNames(scope string, names []string, identifiers []string) error {
+	seen := make(map[string]string, len(identifiers))
+	for i, identifier := range identifiers {
+		if previous, ok := seen[identifier]; ok {
+			return errorx.NewErrorX("'%s' and '%s' of %s both map to '%s'", previous, names[i], scope, identifier)
+		}
+		seen[identifier] = names[i]
+	}
+	return nil
+}
+
+func (g *generator) generateModel(name string, schema *Schema) error {
+	writeComment(&g.models, schema.Description)
+
+	switch {
+	case schema.Ref != "":
+		typ, err := g.typeOf(schema, name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.models, "type %s = %s\n\n", name, typ.expr)
+	case isObject(schema) && !isMap(schema):
+		properties, required, err := g.objectProperties(schema)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.models, "type %s struct {\n", name)
+		err = g.writeFields(name, properties, required)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.models, "}\n\n")
+	case schema.Type == "string" && len(schema.Enum) > 0:
+		fmt.Fprintf(&g.models, "type %s string\n\n", name)
+		fmt.Fprintf(&g.models, "const (\n")
+		for _, value := range schema.Enum {
+			str := fmt.Sprint(value)
+			err := g.declare(name+exportedName(str), fmt.Sprintf("enum value '%s' of '%s'", str, name))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&g.models, "\t%s%s %s = %q\n", name, exportedName(str), name, str)
+		}
+		fmt.Fprintf(&g.models, ")\n\n")
+	default:
+		typ, err := g.typeOf(schema, name+"Item")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.models, "type %s %s\n\n", name, typ.expr)
+	}
+	return nil
+}
+
+func (g *generator) writeFields(typeName string, properties map[string]*Schema, required map[string]bool) error {
+	propertyNames := make([]string, 0, len(properties))
+	for propertyName := range properties {
+		propertyNames = append(propertyNames, propertyName)
+	}
+	sort.Strings(propertyNames)
+
+	fieldNames := make([]string, len(propertyNames))
+	for i, propertyName := range propertyNames {
+		fieldNames[i] = exportedName(propertyName)
+	}
+	err := checkUniqueNames(fmt.Sprintf("properties of '%s'", typeName), propertyNames, fieldNames)
+	if err != nil {
+		return err
+	}
+
+	for _, propertyName := range propertyNames {
+		property := properties[propertyName]
+		fieldName := exportedName(propertyName)
+		typ, err := g.typeOf(property, typeName+fieldName)
+		if err != nil {
+			return err
+		}
+
+		expr := typ.expr
+		tag := propertyName
+		if !required[propertyName] {
+			tag += ",omitempty"
+			if !typ.reference {
+				expr = "*" + expr
+			}
+		} else if property.Nullable && !typ.reference {
+			expr = "*" + expr
+		}
+
+		writeComment(&g.models, property.Description)
+		fmt.Fprintf(&g.models, "\t%s %s `json:%q`\n", fieldName, expr, tag)
+	}
+	return nil
+}
+
+// objectProperties merges the properties of allOf schemas into a single property set.
+func (g *generator) objectProperties(schema *Schema) (map[string]*Schema, map[string]bool, error) {
+	properties := make(map[string]*Schema)
+	required := make(map[string]bool)
+
+	for _, part := range schema.AllOf {
+		_, resolved, err := g.doc.resolveSchema(part)
+		if err != nil {
+			return nil, nil, err
+		}
+		partProperties, partRequired, err := g.objectProperties(resolved)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range partProperties {
+			properties[k] = v
+		}
+		for k := range partRequired {
+			required[k] = true
+		}
+	}
+
+	for k, v := range schema.Properties {
+		properties[k] = v
+	}
+	for _, k := range schema.Required {
+		required[k] = true
+	}
+	return properties, required, nil
+}
+
+// typeOf returns Go type expression of schema. Inline objects are generated as named type using the hint.
+func (g *generator) typeOf(schema *Schema, hint string) (typeInfo, error) {
+	if schema == nil {
+		return typeInfo{expr: "interface{}", reference: true}, nil
+	}
+
+	if schema.Ref != "" {
+		name, resolved, err := g.doc.resolveSchema(schema)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{expr: exportedName(name), reference: g.isReference(resolved, 0)}, nil
+	}
+
+	if len(schema.AllOf) == 1 && len(schema.Properties) == 0 {
+		return g.typeOf(schema.AllOf[0], hint)
+	}
+
+	switch {
+	case isObject(schema):
+		if isMap(schema) {
+			valueType, err := g.additionalPropertiesType(schema, hint+"Value")
+			if err != nil {
+				return typeInfo{}, err
+			}
+			return typeInfo{expr: "map[string]" + valueType.expr, reference: true}, nil
+		}
+		err := g.queue(hint, "inline schema", schema)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{expr: hint}, nil
+	case schema.Type == "array":
+		itemType, err := g.typeOf(schema.Items, hint+"Item")
+		if err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{expr: "[]" + itemType.expr, reference: true}, nil
+	case schema.Type == "string":
+		switch schema.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return typeInfo{expr: "time.Time"}, nil
+		case "byte":
+			return typeInfo{expr: "[]byte", reference: true}, nil
+		}
+		return typeInfo{expr: "string"}, nil
+	case schema.Type == "integer":
+		switch schema.Format {
+		case "int32":
+			return typeInfo{expr: "int32"}, nil
+		case "int64":
+			return typeInfo{expr: "int64"}, nil
+		}
+		return typeInfo{expr: "int"}, nil
+	case schema.Type == "number":
+		if schema.Format == "float" {
+			return typeInfo{expr: "float32"}, nil
+		}
+		return typeInfo{expr: "float64"}, nil
+	case schema.Type == "boolean":
+		return typeInfo{expr: "bool"}, nil
+	}
+	return typeInfo{expr: "interface{}", reference: true}, nil
+}
+
+// isReference reports whether the schema is generated as slice, map or interface type.
+func (g *generator) isReference(schema *Schema, depth int) bool {
+	if schema == nil {
+		return true
+	}
+	if schema.Ref != "" {
+		_, resolved, err := g.doc.resolveSchema(schema)
+		if err != nil || depth > 32 {
+			return false
+		}
+		return g.isReference(resolved, depth+1)
+	}
+	if len(schema.AllOf) == 1 && len(schema.Properties) == 0 {
+		return g.isReference(schema.AllOf[0], depth+1)
+	}
+	switch {
+	case isObject(schema):
+		return isMap(schema)
+	case schema.Type == "array":
+		return true
+	case schema.Type == "string":
+		return schema.Format == "byte"
+	case schema.Type == "integer", schema.Type == "number", schema.Type == "boolean":
+		return false
+	}
+	return true
+}
+
+func (g *generator) additionalPropertiesType(schema *Schema, hint string) (typeInfo, error) {
+	raw := bytes.TrimSpace(schema.AdditionalProperties)
+	if len(raw) == 0 || raw[0] != '{' {
+		return typeInfo{expr: "interface{}", reference: true}, nil
+	}
+	var valueSchema Schema
+	err := json.Unmarshal(raw, &valueSchema)
+	if err != nil {
+		return typeInfo{}, err
+	}
+	return g.typeOf(&valueSchema, hint)
+}
+
+func (g *generator) generateOperations() error {
+	g.imports["context"] = true
+	g.imports["net/http"] = true
+	g.imports[clientxImport] = true
+	g.imports[optionxImport] = true
+
+	err := g.declare(g.cfg.ClientName, "client type")
+	if err != nil {
+		return err
+	}
+	err = g.declare("New"+g.cfg.ClientName, "client constructor")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(&g.operations, "// %s - is typed API client built on clientx.HttpClient.\n", g.cfg.ClientName)
+	fmt.Fprintf(&g.operations, "// Paths are relative, base URL of the API has to be set with clientx.HttpClientCfg.BaseURL.\n")
+	fmt.Fprintf(&g.operations, "type %s struct {\n\thttpClient clientx.HttpClient\n}\n\n", g.cfg.ClientName)
+	fmt.Fprintf(&g.operations, "func New%s(httpClient clientx.HttpClient) *%s {\n\treturn &%s{httpClient: httpClient}\n}\n\n",
+		g.cfg.ClientName, g.cfg.ClientName, g.cfg.ClientName)
+
+	paths := make([]string, 0, len(g.doc.Paths))
+	for path := range g.doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := g.doc.Paths[path]
+		operations := map[string]*Operation{
+			http.MethodGet:    item.Get,
+			http.MethodPost:   item.Post,
+			http.MethodPut:    item.Put,
+			http.MethodPatch:  item.Patch,
+			http.MethodDelete: item.Delete,
+		}
+		for _, method := range httpMethods {
+			operation := operations[method]
+			if operation == nil {
+				continue
+			}
+			err := g.generateOperation(path, method, item, operation)
+			if err != nil {
+				return errorx.NewErrorX("failed to generate operation [%s] %s : %v", method, path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) generateOperation(path string, method string, item *PathItem, operation *Operation) error {
+	opName := operation.OperationID
+	if opName == "" {
+		opName = strings.ToLower(method) + exportedName(path)
+	}
+	methodName := exportedName(opName)
+	if previous, ok := g.methods[methodName]; ok {
+		return errorx.NewErrorX("method name '%s' of operation '%s' collides with operation '%s'", methodName, opName, previous)
+	}
+	g.methods[methodName] = opName
+
+	pathParams, queryParams, headerParams, err := g.parameters(methodName, path, item, operation)
+	if err != nil {
+		return err
+	}
+
+	bodyType, bodyJSON, err := g.requestBodyType(methodName, method, operation)
+	if err != nil {
+		return err
+	}
+
+	resultType, err := g.resultType(methodName, operation)
+	if err != nil {
+		return err
+	}
+
+	paramsType := ""
+	if len(queryParams)+len(headerParams) > 0 {
+		paramsType = methodName + "Params"
+		err = g.declare(paramsType, fmt.Sprintf("parameters of operation '%s'", opName))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.operations, "type %s struct {\n", paramsType)
+		for _, p := range append(append([]parameterInfo{}, queryParams...), headerParams...) {
+			expr := p.typ.expr
+			if !p.param.Required && !p.typ.reference {
+				expr = "*" + expr
+			}
+			writeComment(&g.operations, p.param.Description)
+			fmt.Fprintf(&g.operations, "\t%s %s\n", p.fieldName, expr)
+		}
+		fmt.Fprintf(&g.operations, "}\n\n")
+	}
+
+	// signature
+	args := []string{"ctx context.Context"}
+	for _, p := range pathParams {
+		args = append(args, fmt.Sprintf("%s %s", p.varName, p.typ.expr))
+	}
+	if paramsType != "" {
+		args = append(args, fmt.Sprintf("params *%s", paramsType))
+	}
+	if bodyType != nil {
+		args = append(args, fmt.Sprintf("body %s", bodyType.expr))
+	}
+	args = append(args, "opts ...optionx.Option")
+
+	results := "(*http.Response, error)"
+	if resultType != nil {
+		results = fmt.Sprintf("(%s, *http.Response, error)", resultExpr(*resultType))
+	}
+
+	summary := operation.Summary
+	if summary == "" {
+		summary = fmt.Sprintf("[%s] %s", method, path)
+	}
+	writeComment(&g.operations, fmt.Sprintf("%s - %s", methodName, summary))
+	if operation.Deprecated {
+		writeComment(&g.operations, "Deprecated: the operation is deprecated by the API.")
+	}
+	fmt.Fprintf(&g.operations, "func (c *%s) %s(%s) %s {\n", g.cfg.ClientName, methodName, strings.Join(args, ", "), results)
+
+	zero := "nil, "
+	if resultType == nil {
+		zero = ""
+	}
+
+	// path
+	fmt.Fprintf(&g.operations, "\tpath := %q\n", path)
+	for _, p := range pathParams {
+		g.imports["net/url"] = true
+		g.imports["strings"] = true
+		g.imports["fmt"] = true
+		fmt.Fprintf(&g.operations, "\tpath = strings.Replace(path, %q, url.PathEscape(fmt.Sprint(%s)), 1)\n", "{"+p.param.Name+"}", p.varName)
+	}
+
+	// query
+	if len(queryParams) > 0 {
+		g.imports["net/url"] = true
+		g.imports["fmt"] = true
+		fmt.Fprintf(&g.operations, "\tquery := url.Values{}\n")
+		fmt.Fprintf(&g.operations, "\tif params != nil {\n")
+		for _, p := range queryParams {
+			g.writeParamValue(p, fmt.Sprintf("query.Add(%q, fmt.Sprint(%%s))", p.param.Name))
+		}
+		fmt.Fprintf(&g.operations, "\t}\n")
+		fmt.Fprintf(&g.operations, "\tif len(query) > 0 {\n\t\tpath += \"?\" + query.Encode()\n\t}\n")
+	}
+
+	// options, operation name is put first so that it can be overridden by the caller
+	fmt.Fprintf(&g.operations, "\topts = append([]optionx.Option{clientx.WithOpName(%q)}, opts...)\n", opName)
+	if len(headerParams) > 0 {
+		g.imports["fmt"] = true
+		fmt.Fprintf(&g.operations, "\theader := clientx.Header{}\n")
+		fmt.Fprintf(&g.operations, "\tif params != nil {\n")
+		for _, p := range headerParams {
+			g.writeParamValue(p, fmt.Sprintf("header[%q] = fmt.Sprint(%%s)", p.param.Name))
+		}
+		fmt.Fprintf(&g.operations, "\t}\n")
+		// header parameters are merged into the header of the caller, the caller's values win
+		fmt.Fprintf(&g.operations, "\tif len(header) > 0 {\n\t\topts = append(opts, clientx.WithDefaultHeader(header))\n\t}\n")
+	}
+
+	// body
+	if method != http.MethodGet {
+		g.imports["io"] = true
+		fmt.Fprintf(&g.operations, "\tvar reqBody io.Reader\n")
+		if bodyType != nil {
+			if bodyJSON {
+				g.imports["bytes"] = true
+				g.imports["encoding/json"] = true
+				fmt.Fprintf(&g.operations, "\tif body != nil {\n")
+				fmt.Fprintf(&g.operations, "\t\tdata, err := json.Marshal(body)\n")
+				fmt.Fprintf(&g.operations, "\t\tif err != nil {\n\t\t\treturn %snil, err\n\t\t}\n", zero)
+				fmt.Fprintf(&g.operations, "\t\treqBody = bytes.NewReader(data)\n")
+				fmt.Fprintf(&g.operations, "\t}\n")
+			} else {
+				fmt.Fprintf(&g.operations, "\treqBody = body\n")
+			}
+		}
+		fmt.Fprintf(&g.operations, "\tresp, err := c.httpClient.%s(ctx, path, reqBody, opts...)\n", method)
+	} else {
+		fmt.Fprintf(&g.operations, "\tresp, err := c.httpClient.%s(ctx, path, opts...)\n", method)
+	}
+	fmt.Fprintf(&g.operations, "\tif err != nil {\n\t\treturn %sresp, err\n\t}\n", zero)
+
+	// result
+	if resultType == nil {
+		fmt.Fprintf(&g.operations, "\treturn resp, clientx.DecodeJSONResponse(resp, nil)\n")
+	} else {
+		fmt.Fprintf(&g.operations, "\tvar result %s\n", resultType.expr)
+		fmt.Fprintf(&g.operations, "\terr = clientx.DecodeJSONResponse(resp, &result)\n")
+		fmt.Fprintf(&g.operations, "\tif err != nil {\n\t\treturn nil, resp, err\n\t}\n")
+		if resultType.reference {
+			fmt.Fprintf(&g.operations, "\treturn result, resp, nil\n")
+		} else {
+			fmt.Fprintf(&g.operations, "\treturn &result, resp, nil\n")
+		}
+	}
+	fmt.Fprintf(&g.operations, "}\n\n")
+	return nil
+}
+
+// writeParamValue writes statement for a parameter value, statement has %s placeholder for the value.
+func (g *generator) writeParamValue(p parameterInfo, statement string) {
+	field := "params." + p.fieldName
+	switch {
+	case strings.HasPrefix(p.typ.expr, "[]"):
+		fmt.Fprintf(&g.operations, "\t\tfor _, v := range %s {\n\t\t\t%s\n\t\t}\n", field, fmt.Sprintf(statement, "v"))
+	case p.param.Required || p.typ.reference:
+		fmt.Fprintf(&g.operations, "\t\t%s\n", fmt.Sprintf(statement, field))
+	default:
+		fmt.Fprintf(&g.operations, "\t\tif %s != nil {\n\t\t\t%s\n\t\t}\n", field, fmt.Sprintf(statement, "*"+field))
+	}
+}
+
+func (g *generator) parameters(methodName string, path string, item *PathItem, operation *Operation) ([]parameterInfo, []parameterInfo, []parameterInfo, error) {
+	// operation level parameters override path level parameters with the same name and location
+	merged := make(map[string]*Parameter)
+	var order []string
+	for _, params := range [][]*Parameter{item.Parameters, operation.Parameters} {
+		for _, param := range params {
+			resolved, err := g.doc.resolveParameter(param)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			key := resolved.In + ":" + resolved.Name
+			if _, ok := merged[key]; !ok {
+				order = append(order, key)
+			}
+			merged[key] = resolved
+		}
+	}
+
+	var queryParams, headerParams []parameterInfo
+	pathParamsByName := make(map[string]parameterInfo)
+	for _, key := range order {
+		param := merged[key]
+		typ, err := g.typeOf(param.Schema, methodName+exportedName(param.Name))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		info := parameterInfo{
+			param:     param,
+			fieldName: exportedName(param.Name),
+			varName:   unexportedName(param.Name),
+			typ:       typ,
+		}
+		switch param.In {
+		case "path":
+			pathParamsByName[param.Name] = info
+		case "query":
+			queryParams = append(queryParams, info)
+		case "header":
+			headerParams = append(headerParams, info)
+		}
+	}
+
+	// path parameters are arguments in the order they appear in the path
+	var pathParams []parameterInfo
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		info, ok := pathParamsByName[match[1]]
+		if !ok {
+			info = parameterInfo{
+				param:     &Parameter{Name: match[1], In: "path", Required: true},
+				fieldName: exportedName(match[1]),
+				varName:   unexportedName(match[1]),
+				typ:       typeInfo{expr: "string"},
+			}
+		}
+		pathParams = append(pathParams, info)
+	}
+
+	// path parameters are arguments, query and header parameters are fields of the same struct
+	var names, varNames []string
+	for _, p := range pathParams {
+		names = append(names, p.param.Name)
+		varNames = append(varNames, p.varName)
+	}
+	err := checkUniqueNames("path parameters", names, varNames)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var fieldNames []string
+	names = nil
+	for _, p := range append(append([]parameterInfo{}, queryParams...), headerParams...) {
+		names = append(names, p.param.Name)
+		fieldNames = append(fieldNames, p.fieldName)
+	}
+	err = checkUniqueNames("query and header parameters", names, fieldNames)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pathParams, queryParams, headerParams, nil
+}
+
+func (g *generator) requestBodyType(methodName string, method string, operation *Operation) (*typeInfo, bool, error) {
+	if method == http.MethodGet || operation.RequestBody == nil {
+		return nil, false, nil
+	}
+	body, err := g.doc.resolveRequestBody(operation.RequestBody)
+	if err != nil {
+		return nil, false, err
+	}
+
+	media, ok := jsonMediaType(body.Content)
+	if !ok {
+		g.imports["io"] = true
+		return &typeInfo{expr: "io.Reader", reference: true}, false, nil
+	}
+	typ, err := g.typeOf(media.Schema, methodName+"Request")
+	if err != nil {
+		return nil, false, err
+	}
+	if !typ.reference {
+		typ.expr = "*" + typ.expr
+	}
+	return &typ, true, nil
+}
+
+func (g *generator) resultType(methodName string, operation *Operation) (*typeInfo, error) {
+	codes := make([]string, 0, len(operation.Responses))
+	for code := range operation.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		resp, err := g.doc.resolveResponse(operation.Responses[code])
+		if err != nil {
+			return nil, err
+		}
+		media, ok := jsonMediaType(resp.Content)
+		if !ok || media.Schema == nil {
+			continue
+		}
+		typ, err := g.typeOf(media.Schema, methodName+"Response")
+		if err != nil {
+			return nil, err
+		}
+		return &typ, nil
+	}
+	return nil, nil
+}
+
+func jsonMediaType(content map[string]*MediaType) (*MediaType, bool) {
+	if media, ok := content[applicationJSON]; ok {
+		return media, true
+	}
+	for contentType, media := range content {
+		if strings.HasSuffix(contentType, "+json") {
+			return media, true
+		}
+	}
+	return nil, false
+}
+
+func isObject(schema *Schema) bool {
+	return schema.Type == "object" || (schema.Type == "" && (len(schema.Properties) > 0 || len(schema.AllOf) > 0))
+}
+
+// isMap reports whether the object schema has no declared properties and is generated as map.
+func isMap(schema *Schema) bool {
+	return isObject(schema) && len(schema.Properties) == 0 && len(schema.AllOf) == 0
+}
+
+func resultExpr(typ typeInfo) string {
+	if typ.reference {
+		return typ.expr
+	}
+	return "*" + typ.expr
+}
+
+func writeComment(buf *bytes.Buffer, comment string) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		fmt.Fprintf(buf, "// %s\n", strings.TrimSpace(line))
+	}
+}
@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+var commonInitialisms = map[string]bool{
+	"API":   true,
+	"HTML":  true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"SQL":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UID":   true,
+	"URI":   true,
+	"URL":   true,
+	"UUID":  true,
+	"XML":   true,
+}
+
+// reservedNames are the identifiers used by generated operations and the imported packages, parameters with these
+// names get "Param" suffix like Go keywords.
+var reservedNames = map[string]bool{
+	"c":       true,
+	"ctx":     true,
+	"params":  true,
+	"body":    true,
+	"opts":    true,
+	"path":    true,
+	"query":   true,
+	"header":  true,
+	"reqBody": true,
+	"data":    true,
+	"resp":    true,
+	"err":     true,
+	"result":  true,
+	"v":       true,
+	"bytes":   true,
+	"clientx": true,
+	"context": true,
+	"fmt":     true,
+	"http":    true,
+	"io":      true,
+	"json":    true,
+	"optionx": true,
+	"strings": true,
+	"time":    true,
+	"url":     true,
+}
+
+// exportedName converts names such as "pet_id", "pet-id" and "petId" into "PetID".
+func exportedName(name string) string {
+	words := splitWords(name)
+	var b strings.Builder
+	for _, word := range words {
+		upper := strings.ToUpper(word)
+		if commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	result := b.String()
+	if result == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// unexportedName converts names into lower camel case that can be used as variable name.
+func unexportedName(name string) string {
+	exported := exportedName(name)
+	words := splitWords(name)
+	if len(words) > 0 && commonInitialisms[strings.ToUpper(words[0])] {
+		first := strings.ToLower(words[0])
+		exported = first + exported[len(first):]
+	} else {
+		runes := []rune(exported)
+		runes[0] = unicode.ToLower(runes[0])
+		exported = string(runes)
+	}
+
+	if token.Lookup(exported).IsKeyword() || reservedNames[exported] {
+		return exported + "Param"
+	}
+	return exported
+}
+
+func splitWords(name string) []string {
+	var (
+		words   []string
+		current []rune
+	)
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+
+		// new word starts at lower to upper case boundary, e.g. "petId"
+		if len(current) > 0 && unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) {
+			words = append(words, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
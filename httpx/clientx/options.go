@@ -66,6 +66,27 @@ func WithHeader(obj Header) optionx.Option {
 	}
 }
 
+/*
+	WithDefaultHeader - is to add header keys which are not set by the earlier WithHeader options, e.g. header
+						parameters of generated API client. Header of the earlier options is not modified.
+*/
+func WithDefaultHeader(obj Header) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		header := make(Header, len(obj))
+		for k, v := range obj {
+			header[k] = v
+		}
+		existing, _ := o.Context.Value(httpHeaderKey{}).(Header)
+		for k, v := range existing {
+			header[k] = v
+		}
+		o.Context = context.WithValue(o.Context, httpHeaderKey{}, header)
+	}
+}
+
 /*
 	WithOpName - is to provide operation name for each API call. This name will be used in metric and log to provide meaningful information.
 				 Operation name should not contain space and case insensitive.
@@ -108,3 +129,91 @@ func WithRequestTimeout(timeout time.Duration) optionx.Option {
 		o.Context = context.WithValue(o.Context, httpRequestTimeoutKey{}, timeout)
 	}
 }
+
+/*
+	WithSingleflight - is to turn on or off request coalescing for a single GET call.
+					   This will override the singleflight setting from http client's configuration
+*/
+type singleflightKey struct{}
+
+func WithSingleflight(enabled bool) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, singleflightKey{}, enabled)
+	}
+}
+
+/*
+	WithContractRecorder - is to capture the interactions performed by http client, e.g. into consumer contract.
+*/
+type contractRecorderKey struct{}
+
+func WithContractRecorder(recorder InteractionRecorder) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, contractRecorderKey{}, recorder)
+	}
+}
+
+/*
+	WithBatchConcurrency - is the maximum number of requests of a batch which are executed at the same time. Default is 10.
+*/
+type batchConcurrencyKey struct{}
+
+func WithBatchConcurrency(v int) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, batchConcurrencyKey{}, v)
+	}
+}
+
+/*
+	WithBatchHostConcurrency - is the maximum number of requests of a batch which are executed at the same time for each host.
+							   It is not limited by default.
+*/
+type batchHostConcurrencyKey struct{}
+
+func WithBatchHostConcurrency(v int) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, batchHostConcurrencyKey{}, v)
+	}
+}
+
+/*
+	WithBatchFailFast - is to cancel the rest of the batch as soon as one of the requests fails.
+						By default, all the requests are executed and the errors are collected.
+*/
+type batchFailFastKey struct{}
+
+func WithBatchFailFast(v bool) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, batchFailFastKey{}, v)
+	}
+}
+
+/*
+	WithUpstreamName - is the name of the upstream service which is used to tag the errors decoded from its responses.
+					   Registry sets it to the upstream name of each client.
+*/
+type upstreamNameKey struct{}
+
+func WithUpstreamName(name string) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, upstreamNameKey{}, name)
+	}
+}
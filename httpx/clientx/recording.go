@@ -0,0 +1,51 @@
+package clientx
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+)
+
+/*
+	InteractionRecorder - receives every request and response performed by http client.
+						  It is used to capture consumer contracts, see contract.Recorder.
+*/
+type InteractionRecorder interface {
+	RecordInteraction(opName string, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte)
+}
+
+// recordingTransport - buffers request and response bodies and passes them to InteractionRecorder.
+// It is placed below fault injection so that injected faults are not captured as expected behaviour.
+type recordingTransport struct {
+	next     http.RoundTripper
+	recorder InteractionRecorder
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		reqBody = data
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	opName, _ := req.Context().Value(requestOpNameKey{}).(string)
+	t.recorder.RecordInteraction(opName, req, reqBody, resp, respBody)
+	return resp, nil
+}
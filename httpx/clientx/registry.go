@@ -0,0 +1,205 @@
+package clientx
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kyawmyintthein/orange-contrib/logx"
+	"github.com/kyawmyintthein/orange-contrib/optionx"
+	"github.com/spf13/viper"
+)
+
+/*
+	RegistryCfg - is setting of named upstreams. Each upstream has its own http client setting and base URL.
+				  Note: viper lower cases the configuration keys, so upstream names are lower case when loaded from viper.
+	For example;
+		upstreams:
+			user_service:
+				base_url: "http://user-service:8080/api/v1"
+				default_retry_setting:
+					enabled: true
+*/
+type RegistryCfg struct {
+	Upstreams map[string]HttpClientCfg `json:"upstreams" mapstructure:"upstreams"`
+}
+
+type Registry interface {
+	Get(string) (HttpClient, error)
+	MustGet(string) HttpClient
+	Names() []string
+	Reload(*RegistryCfg) error
+}
+
+type registry struct {
+	opts []optionx.Option
+
+	mu      sync.RWMutex
+	configs map[string]HttpClientCfg
+	clients map[string]HttpClient
+}
+
+/*
+	NewRegistry - builds one http client per upstream. Options such as WithJaeger, WithNewrelic and WithLogger are
+				  shared by all the clients.
+*/
+func NewRegistry(cfg *RegistryCfg, opts ...optionx.Option) (Registry, error) {
+	registry := &registry{
+		opts:    opts,
+		configs: make(map[string]HttpClientCfg),
+		clients: make(map[string]HttpClient),
+	}
+
+	err := registry.Reload(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+func (r *registry) Get(name string) (HttpClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	client, ok := r.clients[name]
+	if !ok {
+		return nil, NewUpstreamNotFoundError(name)
+	}
+	return client, nil
+}
+
+func (r *registry) MustGet(name string) HttpClient {
+	client, err := r.Get(name)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+func (r *registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+	Reload - validates the new setting and swaps the clients. Clients of upstreams whose setting is not changed
+			 are kept as they are, replaced clients are closed once their in-flight calls are finished.
+			 Nothing is changed if any of the upstream setting is invalid.
+*/
+func (r *registry) Reload(cfg *RegistryCfg) error {
+	for name, upstreamCfg := range cfg.Upstreams {
+		err := validateUpstreamCfg(name, upstreamCfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	configs := make(map[string]HttpClientCfg, len(cfg.Upstreams))
+	clients := make(map[string]HttpClient, len(cfg.Upstreams))
+	var built []HttpClient
+	for name, upstreamCfg := range cfg.Upstreams {
+		configs[name] = upstreamCfg
+		oldCfg, ok := r.configs[name]
+		if ok && reflect.DeepEqual(oldCfg, upstreamCfg) {
+			clients[name] = r.clients[name]
+			continue
+		}
+
+		clientCfg := upstreamCfg
+		opts := append([]optionx.Option{WithUpstreamName(name)}, r.opts...)
+		client, err := BuildHttpClient(&clientCfg, opts...)
+		if err != nil {
+			for _, c := range built {
+				c.(*httpClient).Close()
+			}
+			return NewInvalidUpstreamError(name, err.Error())
+		}
+		built = append(built, client)
+		clients[name] = client
+		logx.Infof(context.Background(), "[%s] upstream '%s' is configured with base URL '%s'", PackageName, name, upstreamCfg.BaseURL)
+	}
+
+	// replaced clients are closed once the calls which got them before reload are finished
+	for name, client := range r.clients {
+		if clients[name] != client {
+			go client.(*httpClient).closeWhenIdle()
+		}
+	}
+
+	r.configs = configs
+	r.clients = clients
+	return nil
+}
+
+/*
+	WatchRegistryCfg - reloads the registry from viper whenever the configuration file is changed.
+					   Note: viper keeps only one config change handler and this function installs its own, so any
+					   handler registered before with v.OnConfigChange is replaced. Other handlers of the same viper
+					   instance should be passed as onChange instead, they are called after the registry is reloaded.
+*/
+func WatchRegistryCfg(v *viper.Viper, key string, r Registry, onChange ...func(fsnotify.Event)) {
+	v.OnConfigChange(func(ev fsnotify.Event) {
+		reloadRegistry(v, key, r, ev)
+		for _, fn := range onChange {
+			fn(ev)
+		}
+	})
+	v.WatchConfig()
+}
+
+func reloadRegistry(v *viper.Viper, key string, r Registry, ev fsnotify.Event) {
+	var cfg RegistryCfg
+	err := v.UnmarshalKey(key, &cfg)
+	if err != nil {
+		logx.Errorf(context.Background(), err, "[%s] failed to read upstream registry setting from '%s'", PackageName, ev.Name)
+		return
+	}
+
+	err = r.Reload(&cfg)
+	if err != nil {
+		logx.Errorf(context.Background(), err, "[%s] failed to reload upstream registry, keep using the previous setting", PackageName)
+		return
+	}
+	logx.Infof(context.Background(), "[%s] upstream registry is reloaded", PackageName)
+}
+
+func validateUpstreamCfg(name string, cfg HttpClientCfg) error {
+	baseURL, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return NewInvalidUpstreamError(name, err.Error())
+	}
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return NewInvalidUpstreamError(name, "base URL must be absolute")
+	}
+	return nil
+}
+
+// resolveURL joins relative path with base URL. Absolute URL is returned as it is.
+func resolveURL(baseURL string, path string) string {
+	if baseURL == "" {
+		return path
+	}
+	u, err := url.Parse(path)
+	if err == nil && u.IsAbs() {
+		return path
+	}
+	if path == "" {
+		return baseURL
+	}
+	if strings.HasPrefix(path, "?") {
+		return strings.TrimRight(baseURL, "/") + path
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
@@ -0,0 +1,84 @@
+package clientx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+)
+
+const (
+	maxErrorBodySize int64 = 64 * 1024
+)
+
+/*
+	DecodeJSONResponse - decodes JSON body of 2xx response into v and closes the body.
+						 For other status codes, RemoteError is returned if the body is in errorx wire format,
+						 UnexpectedStatusError otherwise. Body is discarded if v is nil.
+*/
+func DecodeJSONResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return DecodeErrorResponse(resp)
+	}
+
+	if v == nil {
+		_, err := io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+
+	err := json.NewDecoder(resp.Body).Decode(v)
+	if err == io.EOF {
+		// empty body, e.g. 204 No Content
+		return nil
+	}
+	return err
+}
+
+/*
+	DecodeErrorResponse - reads the error response and closes the body. RemoteError tagged with upstream name is returned
+						  if the body is in errorx wire format. The upstream name is the registry name of the client,
+						  or host of the URL for the clients which are not created by registry.
+*/
+func DecodeErrorResponse(resp *http.Response) error {
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	var envelope errorx.WireEnvelope
+	err := json.NewDecoder(bytes.NewReader(body)).Decode(&envelope)
+	if err != nil || envelope.Error == nil || envelope.Error.Message == "" {
+		return NewUnexpectedStatusError(responseURL(resp), resp.StatusCode, body)
+	}
+	if envelope.Error.Status == 0 {
+		envelope.Error.Status = resp.StatusCode
+	}
+	if envelope.Error.Code == 0 {
+		envelope.Error.Code = errorx.ErrorCodeFromHttpStatus(envelope.Error.Status)
+	}
+	return NewRemoteError(upstreamName(resp), envelope.Error)
+}
+
+func upstreamName(resp *http.Response) string {
+	if resp.Request == nil {
+		return ""
+	}
+	name, ok := resp.Request.Context().Value(upstreamNameKey{}).(string)
+	if ok && name != "" {
+		return name
+	}
+	if resp.Request.URL == nil {
+		return ""
+	}
+	return resp.Request.URL.Host
+}
+
+func responseURL(resp *http.Response) string {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return ""
+	}
+	return resp.Request.URL.String()
+}
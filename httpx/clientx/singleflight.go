@@ -0,0 +1,111 @@
+package clientx
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// singleflightGroup - deduplicates identical in-flight requests. The first caller of a key performs the request,
+// every other caller with the same key waits for it and receives a copy of the response.
+type singleflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*singleflightCall
+}
+
+type singleflightCall struct {
+	done     chan struct{}
+	resp     *http.Response
+	body     []byte
+	err      error
+	dups     int
+	canceled bool // the leader's context was done, the error is not shared with the waiters
+}
+
+func newSingleflightGroup() *singleflightGroup {
+	return &singleflightGroup{
+		calls: make(map[string]*singleflightCall),
+	}
+}
+
+// do executes fn once per key at a time and returns the response, the number of callers coalesced into the call
+// and whether the response was shared with the caller instead of fetched by it. A waiter stops waiting when its own
+// ctx is done. When the call fails because the leader's ctx is done, the leader's cancellation is not handed to the
+// waiters, one of them performs the request with its own fn instead.
+func (g *singleflightGroup) do(ctx context.Context, key string, fn func() (*http.Response, error)) (*http.Response, int, bool, error) {
+	for {
+		g.mu.Lock()
+		c, ok := g.calls[key]
+		if !ok {
+			break
+		}
+		c.dups++
+		dups := c.dups
+		g.mu.Unlock()
+
+		select {
+		case <-c.done:
+			if c.canceled {
+				continue
+			}
+			return c.response(), c.dups, true, c.err
+		case <-ctx.Done():
+			return nil, dups, true, ctx.Err()
+		}
+	}
+	c := &singleflightCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.resp, c.err = fn()
+	if c.err != nil && ctx.Err() != nil {
+		c.canceled = true
+	}
+	if c.resp != nil && c.resp.Body != nil {
+		// the body is buffered so that every waiter can read it independently, the original body is always closed
+		body, err := ioutil.ReadAll(c.resp.Body)
+		c.resp.Body.Close()
+		c.body = body
+		if c.err == nil {
+			c.err = err
+		}
+	}
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(c.done)
+
+	return c.response(), c.dups, false, c.err
+}
+
+func (c *singleflightCall) response() *http.Response {
+	if c.resp == nil {
+		return nil
+	}
+	resp := *c.resp
+	resp.Header = c.resp.Header.Clone()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(c.body))
+	return &resp
+}
+
+func buildSingleflightKey(req *http.Request, headers []string) string {
+	var key strings.Builder
+	key.WriteString(req.Method)
+	key.WriteString(" ")
+	key.WriteString(req.URL.String())
+
+	sortedHeaders := append([]string(nil), headers...)
+	sort.Strings(sortedHeaders)
+	for _, h := range sortedHeaders {
+		key.WriteString("\n")
+		key.WriteString(http.CanonicalHeaderKey(h))
+		key.WriteString(":")
+		key.WriteString(strings.Join(req.Header.Values(h), ","))
+	}
+	return key.String()
+}
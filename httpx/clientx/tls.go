@@ -0,0 +1,251 @@
+package clientx
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kyawmyintthein/orange-contrib/logx"
+)
+
+const (
+	spiffeScheme          string        = "spiffe"
+	certificateReloadWait time.Duration = 500 * time.Millisecond
+)
+
+type TLSInspector interface {
+	// ClientCertificateExpiry returns expiry time of the active client certificate and false if mutual TLS is not enabled.
+	ClientCertificateExpiry() (time.Time, bool)
+}
+
+// certificateReloader - keeps the client certificate and CA pool configured in TLSCfg and swaps them
+// whenever the files change on disk, so that rotated certificates are used without restart.
+type certificateReloader struct {
+	cfg       *TLSCfg
+	transport *http.Transport
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu    sync.RWMutex
+	cert  *tls.Certificate
+	leaf  *x509.Certificate
+	roots *x509.CertPool
+}
+
+func newCertificateReloader(cfg *TLSCfg) (*certificateReloader, error) {
+	reloader := &certificateReloader{
+		cfg:  cfg,
+		done: make(chan struct{}),
+	}
+	err := reloader.load()
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	// directories are watched instead of files because secret volumes replace files by swapping symlinks
+	dirs := make(map[string]struct{})
+	for _, file := range []string{cfg.CertFile, cfg.KeyFile, cfg.CAFile} {
+		if file == "" {
+			continue
+		}
+		dirs[filepath.Dir(file)] = struct{}{}
+	}
+	for dir := range dirs {
+		err = watcher.Add(dir)
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+
+	go reloader.watch(watcher)
+	return reloader, nil
+}
+
+func (r *certificateReloader) watch(watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-r.done:
+			return
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			// a rotation touches several files, wait until the writes are settled before reloading
+			reload = time.After(certificateReloadWait)
+		case <-reload:
+			reload = nil
+			err := r.load()
+			if err != nil {
+				logx.Errorf(context.Background(), err, "[%s] failed to reload client certificate, keep using the previous one", PackageName)
+				continue
+			}
+			if r.transport != nil {
+				r.transport.CloseIdleConnections()
+			}
+			expiry, _ := r.expiry()
+			logx.InfoKVf(context.Background(), logx.KV{"NotAfter": expiry}, "[%s] reloaded client certificate", PackageName)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logx.Errorf(context.Background(), err, "[%s] failed to watch certificate files", PackageName)
+		}
+	}
+}
+
+// close stops watching the certificate files.
+func (r *certificateReloader) close() {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+}
+
+func (r *certificateReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+	cert.Leaf = leaf
+
+	var roots *x509.CertPool
+	if r.cfg.CAFile != "" {
+		caData, err := ioutil.ReadFile(r.cfg.CAFile)
+		if err != nil {
+			return err
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caData) {
+			return NewInvalidCertificateError(r.cfg.CAFile)
+		}
+	} else {
+		roots, err = x509.SystemCertPool()
+		if err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.leaf = leaf
+	r.roots = roots
+	return nil
+}
+
+func (r *certificateReloader) expiry() (time.Time, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.leaf == nil {
+		return time.Time{}, false
+	}
+	return r.leaf.NotAfter, true
+}
+
+func (r *certificateReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:           tls.VersionTLS12,
+		ServerName:           r.cfg.ServerName,
+		GetClientCertificate: r.getClientCertificate,
+		// the chain is verified in verifyConnection against the CA pool which can be reloaded at runtime,
+		// the static verification has to be skipped because it can only use a fixed RootCAs.
+		// VerifyConnection requires go 1.15, VerifyPeerCertificate can not be used since it has no server name.
+		InsecureSkipVerify: true,
+		VerifyConnection:   r.verifyConnection,
+	}
+}
+
+func (r *certificateReloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *certificateReloader) verifyConnection(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return NewInvalidServerCertificateError(state.ServerName, "no certificate presented")
+	}
+
+	r.mu.RLock()
+	roots := r.roots
+	r.mu.RUnlock()
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	// SPIFFE identities are carried in URI SAN, host name verification only applies to non SPIFFE setup
+	spiffeVerification := len(r.cfg.AllowedSPIFFEIDs) > 0 || r.cfg.TrustDomain != ""
+	if !spiffeVerification {
+		opts.DNSName = state.ServerName
+	}
+
+	_, err := state.PeerCertificates[0].Verify(opts)
+	if err != nil {
+		return err
+	}
+
+	if spiffeVerification {
+		return r.verifySPIFFEID(state.ServerName, state.PeerCertificates[0])
+	}
+	return nil
+}
+
+// failingTransport rejects every request of the client whose mutual TLS could not be set up.
+type failingTransport struct {
+	err error
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
+
+func (r *certificateReloader) verifySPIFFEID(serverName string, cert *x509.Certificate) error {
+	for _, uri := range cert.URIs {
+		if uri.Scheme != spiffeScheme {
+			continue
+		}
+		if len(r.cfg.AllowedSPIFFEIDs) == 0 {
+			if strings.EqualFold(uri.Host, strings.TrimPrefix(r.cfg.TrustDomain, "spiffe://")) {
+				return nil
+			}
+			continue
+		}
+		for _, allowedID := range r.cfg.AllowedSPIFFEIDs {
+			if uri.String() == allowedID {
+				return nil
+			}
+		}
+	}
+	return NewInvalidServerCertificateError(serverName, "SPIFFE ID is not allowed")
+}
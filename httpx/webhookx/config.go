@@ -0,0 +1,23 @@
+package webhookx
+
+import "time"
+
+type WebhookCfg struct {
+	Workers              int                    `json:"workers" mapstructure:"workers"`
+	PollInterval         time.Duration          `json:"poll_interval" mapstructure:"poll_interval"`
+	BatchSize            int                    `json:"batch_size" mapstructure:"batch_size"`
+	DefaultRetrySchedule []time.Duration        `json:"default_retry_schedule" mapstructure:"default_retry_schedule"`
+	Endpoints            map[string]EndpointCfg `json:"endpoints" mapstructure:"endpoints"`
+}
+
+/*
+	EndpointCfg - is setting of a customer endpoint. RetrySchedule is the wait time before each retry,
+				  delivery is moved to dead letter list when all the retries are failed. For example;
+					retry_schedule: ["1m", "10m", "1h", "6h"]
+*/
+type EndpointCfg struct {
+	URL           string            `json:"url" mapstructure:"url"`
+	Secret        string            `json:"secret" mapstructure:"secret"`
+	Headers       map[string]string `json:"headers" mapstructure:"headers"`
+	RetrySchedule []time.Duration   `json:"retry_schedule" mapstructure:"retry_schedule"`
+}
@@ -0,0 +1,52 @@
+package webhookx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+type DeliveryStatus string
+
+const (
+	StatusPending   DeliveryStatus = "pending"
+	StatusDelivered DeliveryStatus = "delivered"
+	StatusDead      DeliveryStatus = "dead"
+)
+
+type Delivery struct {
+	ID            string         `json:"id"`
+	Endpoint      string         `json:"endpoint"`
+	Event         string         `json:"event"`
+	Payload       []byte         `json:"payload"`
+	Status        DeliveryStatus `json:"status"`
+	Attempts      []Attempt      `json:"attempts"`
+	Retries       int            `json:"retries"` // retries scheduled since it is sent or redelivered
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+type Attempt struct {
+	StartedAt  time.Time     `json:"started_at"`
+	Duration   time.Duration `json:"duration"`
+	StatusCode int           `json:"status_code"`
+	Error      string        `json:"error,omitempty"`
+}
+
+func (d *Delivery) clone() *Delivery {
+	c := *d
+	c.Payload = append([]byte(nil), d.Payload...)
+	c.Attempts = append([]Attempt(nil), d.Attempts...)
+	return &c
+}
+
+func newDeliveryID() string {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		// crypto/rand does not fail on supported platforms, fall back to time based ID just in case
+		return hex.EncodeToString([]byte(time.Now().Format(time.RFC3339Nano)))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,333 @@
+package webhookx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kyawmyintthein/orange-contrib/httpx/clientx"
+	"github.com/kyawmyintthein/orange-contrib/logx"
+	"github.com/kyawmyintthein/orange-contrib/optionx"
+)
+
+const (
+	defaultWorkers      int           = 4
+	defaultPollInterval time.Duration = time.Second
+	defaultBatchSize    int           = 100
+)
+
+var defaultRetrySchedule = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+}
+
+type Dispatcher interface {
+	// Send persists the delivery and returns immediately, the delivery is made by background workers.
+	Send(ctx context.Context, endpoint string, event string, payload []byte) (*Delivery, error)
+	Start()
+	Stop()
+
+	Delivery(ctx context.Context, id string) (*Delivery, error)
+	DeadLetters(ctx context.Context) ([]*Delivery, error)
+	// Redeliver schedules the delivery for immediate attempt, previous attempts are kept for inspection.
+	Redeliver(ctx context.Context, id string) error
+}
+
+type dispatcher struct {
+	cfg    *WebhookCfg
+	client clientx.HttpClient
+	store  Store
+
+	trigger chan struct{}
+	queue   chan *Delivery
+	stop    chan struct{}
+	wg      sync.WaitGroup
+
+	mu       sync.Mutex
+	inflight map[string]struct{}
+	started  bool
+}
+
+/*
+	NewDispatcher - creates webhook dispatcher on top of the given http client.
+					Retry of the http client, both default and API specific setting, is turned off for webhook calls
+					with WithRetrySetting option, so that each delivery attempt sends exactly one request. Retries are
+					scheduled by the dispatcher with endpoint's retry schedule instead.
+*/
+func NewDispatcher(cfg *WebhookCfg, client clientx.HttpClient, store Store) Dispatcher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if len(cfg.DefaultRetrySchedule) == 0 {
+		cfg.DefaultRetrySchedule = defaultRetrySchedule
+	}
+	if store == nil {
+		store = NewMemoryStore()
+	}
+
+	return &dispatcher{
+		cfg:      cfg,
+		client:   client,
+		store:    store,
+		trigger:  make(chan struct{}, 1),
+		queue:    make(chan *Delivery),
+		inflight: make(map[string]struct{}),
+	}
+}
+
+func (d *dispatcher) Send(ctx context.Context, endpoint string, event string, payload []byte) (*Delivery, error) {
+	_, ok := d.cfg.Endpoints[endpoint]
+	if !ok {
+		return nil, NewEndpointNotFoundError(endpoint)
+	}
+
+	now := time.Now()
+	delivery := &Delivery{
+		ID:            newDeliveryID(),
+		Endpoint:      endpoint,
+		Event:         event,
+		Payload:       payload,
+		Status:        StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	err := d.store.Save(ctx, delivery)
+	if err != nil {
+		return nil, err
+	}
+	d.wakeUp()
+	return delivery.clone(), nil
+}
+
+func (d *dispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started {
+		return
+	}
+	d.started = true
+	d.stop = make(chan struct{})
+
+	for i := 0; i < d.cfg.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	d.wg.Add(1)
+	go d.poll()
+}
+
+// Stop waits for the in-flight deliveries to finish. Pending deliveries stay in the store.
+func (d *dispatcher) Stop() {
+	d.mu.Lock()
+	if !d.started {
+		d.mu.Unlock()
+		return
+	}
+	d.started = false
+	close(d.stop)
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+func (d *dispatcher) Delivery(ctx context.Context, id string) (*Delivery, error) {
+	return d.store.Get(ctx, id)
+}
+
+func (d *dispatcher) DeadLetters(ctx context.Context) ([]*Delivery, error) {
+	return d.store.List(ctx, StatusDead)
+}
+
+func (d *dispatcher) Redeliver(ctx context.Context, id string) error {
+	delivery, err := d.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	// redelivered webhook goes through the whole retry schedule again
+	delivery.Status = StatusPending
+	delivery.Retries = 0
+	delivery.NextAttemptAt = time.Now()
+	delivery.UpdatedAt = delivery.NextAttemptAt
+	err = d.store.Save(ctx, delivery)
+	if err != nil {
+		return err
+	}
+	d.wakeUp()
+	return nil
+}
+
+func (d *dispatcher) wakeUp() {
+	select {
+	case d.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (d *dispatcher) poll() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		case <-d.trigger:
+		}
+
+		due, err := d.store.Due(context.Background(), time.Now(), d.cfg.BatchSize)
+		if err != nil {
+			logx.Errorf(context.Background(), err, "[%s] failed to load due deliveries", PackageName)
+			continue
+		}
+		for _, delivery := range due {
+			if !d.claim(delivery.ID) {
+				continue
+			}
+			select {
+			case d.queue <- delivery:
+			case <-d.stop:
+				d.release(delivery.ID)
+				return
+			}
+		}
+	}
+}
+
+func (d *dispatcher) work() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case delivery := <-d.queue:
+			d.deliver(context.Background(), delivery)
+			d.release(delivery.ID)
+		}
+	}
+}
+
+// claim prevents the same delivery from being picked up again by the next poll while it is in progress.
+func (d *dispatcher) claim(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.inflight[id]
+	if ok {
+		return false
+	}
+	d.inflight[id] = struct{}{}
+	return true
+}
+
+func (d *dispatcher) release(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inflight, id)
+}
+
+func (d *dispatcher) deliver(ctx context.Context, delivery *Delivery) {
+	endpoint, ok := d.cfg.Endpoints[delivery.Endpoint]
+	if !ok {
+		err := NewEndpointNotFoundError(delivery.Endpoint)
+		logx.Errorf(ctx, err, "[%s] delivery '%s' is moved to dead letter", PackageName, delivery.ID)
+		d.finish(ctx, delivery, StatusDead)
+		return
+	}
+
+	attempt := Attempt{
+		StartedAt: time.Now(),
+	}
+	statusCode, err := d.post(ctx, endpoint, delivery)
+	attempt.Duration = time.Since(attempt.StartedAt)
+	attempt.StatusCode = statusCode
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	kv := logx.KV{
+		"DeliveryID": delivery.ID,
+		"Endpoint":   delivery.Endpoint,
+		"Event":      delivery.Event,
+		"Attempt":    len(delivery.Attempts),
+	}
+	if err == nil {
+		d.finish(ctx, delivery, StatusDelivered)
+		return
+	}
+
+	schedule := endpoint.RetrySchedule
+	if len(schedule) == 0 {
+		schedule = d.cfg.DefaultRetrySchedule
+	}
+	if delivery.Retries >= len(schedule) {
+		logx.ErrorKVf(ctx, err, kv, "[%s] delivery is moved to dead letter after %d attempts", PackageName, len(delivery.Attempts))
+		d.finish(ctx, delivery, StatusDead)
+		return
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(schedule[delivery.Retries])
+	delivery.Retries++
+	logx.WarnKVf(ctx, kv, "[%s] delivery failed : %s, next attempt at %s", PackageName, err.Error(), delivery.NextAttemptAt.Format(time.RFC3339))
+	d.finish(ctx, delivery, StatusPending)
+}
+
+func (d *dispatcher) finish(ctx context.Context, delivery *Delivery, status DeliveryStatus) {
+	delivery.Status = status
+	delivery.UpdatedAt = time.Now()
+	err := d.store.Save(ctx, delivery)
+	if err != nil {
+		logx.Errorf(ctx, err, "[%s] failed to save delivery '%s'", PackageName, delivery.ID)
+	}
+}
+
+func (d *dispatcher) post(ctx context.Context, endpoint EndpointCfg, delivery *Delivery) (int, error) {
+	timestamp := time.Now().Unix()
+	header := clientx.Header{}
+	for k, v := range endpoint.Headers {
+		header[k] = v
+	}
+	header[HeaderDeliveryID] = delivery.ID
+	header[HeaderEvent] = delivery.Event
+	header[HeaderTimestamp] = strconv.FormatInt(timestamp, 10)
+	header[HeaderSignature] = Sign(endpoint.Secret, timestamp, delivery.Payload)
+
+	opts := []optionx.Option{
+		clientx.WithHeader(header),
+		clientx.WithOpName("webhook::" + delivery.Endpoint),
+		clientx.WithRetrySetting(&clientx.RetryCfg{Enabled: false}),
+	}
+	resp, err := d.client.POST(ctx, endpoint.URL, bytes.NewReader(delivery.Payload), opts...)
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+		// body is drained so that the connection can be reused
+		defer io.Copy(ioutil.Discard, resp.Body)
+	}
+	if err != nil {
+		if resp != nil {
+			return resp.StatusCode, err
+		}
+		return 0, err
+	}
+	if resp == nil {
+		return 0, NewDeliveryFailedError(endpoint.URL, 0)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, NewDeliveryFailedError(endpoint.URL, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,43 @@
+package webhookx
+
+import "github.com/kyawmyintthein/orange-contrib/errorx"
+
+type EndpointNotFoundError struct {
+	*errorx.ErrorX
+}
+
+func NewEndpointNotFoundError(endpoint string) *EndpointNotFoundError {
+	return &EndpointNotFoundError{
+		errorx.NewErrorX("[%s] endpoint '%s' is not configured", PackageName, endpoint),
+	}
+}
+
+type DeliveryNotFoundError struct {
+	*errorx.ErrorX
+}
+
+func NewDeliveryNotFoundError(id string) *DeliveryNotFoundError {
+	return &DeliveryNotFoundError{
+		errorx.NewErrorX("[%s] delivery '%s' is not found", PackageName, id),
+	}
+}
+
+type InvalidSignatureError struct {
+	*errorx.ErrorX
+}
+
+func NewInvalidSignatureError(reason string) *InvalidSignatureError {
+	return &InvalidSignatureError{
+		errorx.NewErrorX("[%s] invalid webhook signature : %s", PackageName, reason),
+	}
+}
+
+type DeliveryFailedError struct {
+	*errorx.ErrorX
+}
+
+func NewDeliveryFailedError(url string, statusCode int) *DeliveryFailedError {
+	return &DeliveryFailedError{
+		errorx.NewErrorX("[%s] endpoint returned status code : %d from URL: %s", PackageName, statusCode, url),
+	}
+}
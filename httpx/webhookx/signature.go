@@ -0,0 +1,52 @@
+package webhookx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderDeliveryID = "X-Webhook-ID"
+	HeaderEvent      = "X-Webhook-Event"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+
+	signatureVersion = "v1"
+)
+
+/*
+	Sign - returns HMAC-SHA256 signature of the payload in format "v1=<hex>".
+		   Timestamp is part of the signed content, "<timestamp>.<payload>", so that the request cannot be replayed later.
+*/
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature is for the receiver side to check the signature and the age of the request.
+func VerifySignature(secret string, timestampHeader string, signatureHeader string, payload []byte, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return NewInvalidSignatureError("invalid timestamp")
+	}
+
+	age := time.Since(time.Unix(timestamp, 0))
+	if tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return NewInvalidSignatureError("timestamp is out of tolerance")
+	}
+
+	expected := Sign(secret, timestamp, payload)
+	for _, signature := range strings.Split(signatureHeader, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(signature)), []byte(expected)) {
+			return nil
+		}
+	}
+	return NewInvalidSignatureError("signature does not match")
+}
@@ -0,0 +1,157 @@
+package webhookx
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+	Store - persists deliveries so that pending deliveries survive restart of the application.
+			Implementations must return copies, deliveries are modified by the dispatcher after they are loaded.
+*/
+type Store interface {
+	Save(context.Context, *Delivery) error
+	Get(context.Context, string) (*Delivery, error)
+	Due(context.Context, time.Time, int) ([]*Delivery, error)
+	List(context.Context, DeliveryStatus) ([]*Delivery, error)
+}
+
+type memoryStore struct {
+	mu         sync.RWMutex
+	deliveries map[string]*Delivery
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		deliveries: make(map[string]*Delivery),
+	}
+}
+
+func (s *memoryStore) Save(ctx context.Context, delivery *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries[delivery.ID] = delivery.clone()
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, id string) (*Delivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	delivery, ok := s.deliveries[id]
+	if !ok {
+		return nil, NewDeliveryNotFoundError(id)
+	}
+	return delivery.clone(), nil
+}
+
+func (s *memoryStore) Due(ctx context.Context, now time.Time, limit int) ([]*Delivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var due []*Delivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status == StatusPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery.clone())
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (s *memoryStore) List(ctx context.Context, status DeliveryStatus) ([]*Delivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var deliveries []*Delivery
+	for _, delivery := range s.deliveries {
+		if status == "" || delivery.Status == status {
+			deliveries = append(deliveries, delivery.clone())
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+	return deliveries, nil
+}
+
+/*
+	fileStore - keeps deliveries in memory and writes all of them into a JSON file on every change.
+				It is meant for single instance applications with moderate webhook volume.
+*/
+type fileStore struct {
+	*memoryStore
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileStore(path string) (Store, error) {
+	store := &fileStore{
+		memoryStore: &memoryStore{
+			deliveries: make(map[string]*Delivery),
+		},
+		path: path,
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		var deliveries []*Delivery
+		err = json.Unmarshal(data, &deliveries)
+		if err != nil {
+			return nil, err
+		}
+		for _, delivery := range deliveries {
+			store.deliveries[delivery.ID] = delivery
+		}
+	}
+	return store, nil
+}
+
+func (s *fileStore) Save(ctx context.Context, delivery *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.memoryStore.Save(ctx, delivery)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := s.memoryStore.List(ctx, "")
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(deliveries)
+	if err != nil {
+		return err
+	}
+
+	// write into temporary file and rename it, so that the file is never left half written
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,6 @@
+package webhookx
+
+const (
+	PackageName = "WebhookX"
+	Version     = "v0.0.1"
+)
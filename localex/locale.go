@@ -3,6 +3,7 @@ package localex
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/kyawmyintthein/orange-contrib/localex/storage"
@@ -39,11 +40,11 @@ func (t *translator) Translate(ctx context.Context, messageID string, argKvs ...
 
 	if t.cfg.Enabled {
 
-		locale, _ := ctx.Value(X_LOCALE).(string)
+		locale := Locale(ctx)
 		if locale == "" {
 			locale = t.cfg.DefaultLocale
 		}
-		localizedString := t.storage.GetLocalizedMessage(messageID, locale)
+		localizedString := t.storage.GetLocalizedMessage(locale, messageID)
 		if localizedString != "" {
 			translatedString = localizedString
 		}
@@ -51,12 +52,8 @@ func (t *translator) Translate(ctx context.Context, messageID string, argKvs ...
 
 	if len(argKvs) != 0 {
 		argsMap := make(map[string]string)
-		previousKey := ""
-		for _, v := range argKvs {
-			if previousKey != "" {
-				argsMap[previousKey] = v
-			}
-			previousKey = v
+		for i := 0; i+1 < len(argKvs); i += 2 {
+			argsMap[argKvs[i]] = argKvs[i+1]
 		}
 
 		for k, v := range argsMap {
@@ -66,3 +63,31 @@ func (t *translator) Translate(ctx context.Context, messageID string, argKvs ...
 	}
 	return translatedString
 }
+
+// WithLocale puts the locale into context, it is used by Translate.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, X_LOCALE, locale)
+}
+
+func Locale(ctx context.Context) string {
+	locale, _ := ctx.Value(X_LOCALE).(string)
+	return locale
+}
+
+/*
+	LocaleMiddleware - takes the request locale from X-LOCALE header, or the first language of Accept-Language header,
+					   and puts it into the request context.
+*/
+func LocaleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := strings.TrimSpace(r.Header.Get(X_LOCALE))
+		if locale == "" {
+			language := strings.Split(r.Header.Get("Accept-Language"), ",")[0]
+			locale = strings.TrimSpace(strings.Split(language, ";")[0])
+		}
+		if locale != "" && locale != "*" {
+			r = r.WithContext(WithLocale(r.Context(), locale))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
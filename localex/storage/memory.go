@@ -1,8 +1,10 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/kyawmyintthein/orange-contrib/logx"
 	"github.com/spf13/viper"
 )
 
@@ -15,15 +17,21 @@ type memoryStorage struct {
 	viper *viper.Viper
 }
 
+/*
+	NewMemoryStorage - loads the locale files into memory, messages of the later files override the earlier ones.
+					   Files which can not be read are skipped with error log.
+*/
 func NewMemoryStorage(cfg *MemoryStorageCfg) Storage {
 	memoryStorage := memoryStorage{
 		cfg:   cfg,
 		viper: viper.New(),
 	}
-	memoryStorage.viper.SetConfigName("locale")
-	for _, filepath := range cfg.LocaleFiles {
-		viper.AddConfigPath(filepath)
-		viper.MergeInConfig()
+	for _, file := range cfg.LocaleFiles {
+		memoryStorage.viper.SetConfigFile(file)
+		err := memoryStorage.viper.MergeInConfig()
+		if err != nil {
+			logx.Errorf(context.Background(), err, "[LocaleX] failed to load locale file '%s'", file)
+		}
 	}
 	return &memoryStorage
 }
@@ -1,5 +1,6 @@
 package storage
 
 type Storage interface {
-	GetLocalizedMessage(string, string) string
+	// GetLocalizedMessage returns empty string if there is no message of the key in the locale.
+	GetLocalizedMessage(locale string, key string) string
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/kyawmyintthein/orange-contrib/logx"
+	"github.com/sirupsen/logrus"
+)
+
+// compares the logrus adapter with the native JSON backend, run with: go run ./logx/_example/benchmark
+func main() {
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	logrusLogger.SetFormatter(&logrus.JSONFormatter{})
+
+	backends := []struct {
+		name    string
+		handler logx.Handler
+	}{
+		{"logrus", logx.NewLogrusHandler(logrusLogger)},
+		{"native", logx.NewJSONHandler(ioutil.Discard, logx.InfoLevel)},
+	}
+
+	ctx := context.Background()
+	err := errors.New("connection refused")
+	for _, backend := range backends {
+		logger := logx.New(&logx.LogCfg{}, logx.WithHandler(backend.handler))
+
+		result := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.InfoKVf(ctx, logx.KV{"user_id": 42, "path": "/users/42", "elapsed_ms": 3.5}, "request %d served", i)
+			}
+		})
+		fmt.Printf("%-8s info  %s %s\n", backend.name, result.String(), result.MemString())
+
+		result = testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.ErrorKVf(ctx, err, logx.KV{"user_id": 42}, "failed to load user")
+			}
+		})
+		fmt.Printf("%-8s error %s %s\n", backend.name, result.String(), result.MemString())
+
+		result = testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.Debugf(ctx, "disabled level %d", i)
+			}
+		})
+		fmt.Printf("%-8s debug %s %s\n", backend.name, result.String(), result.MemString())
+	}
+}
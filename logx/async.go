@@ -0,0 +1,279 @@
+package logx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	blockPolicy      = "block"
+	dropNewestPolicy = "drop_newest"
+	dropOldestPolicy = "drop_oldest"
+
+	defaultAsyncBufferSize int = 1024
+	defaultAsyncBatchSize  int = 64
+)
+
+type asyncEntry struct {
+	level Level
+	data  []byte
+}
+
+/*
+	asyncWriter - queues the encoded records in a bounded ring buffer and writes them in batches from a background
+				  goroutine, so that callers are not blocked by the output. When the buffer is full, the record is
+				  handled by the overflow policy;
+					block       : the caller waits for free space
+					drop_newest : the new record is dropped
+					drop_oldest : the oldest record of the lowest level, not higher than the new record, is dropped.
+								  The new record is dropped if all the queued records have higher level.
+				  Records written after Close are written synchronously.
+*/
+type asyncWriter struct {
+	dropped uint64 // first field to be 64-bit aligned for atomic operations
+
+	policy    string
+	batchSize int
+	write     func(batch []asyncEntry) error
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	entries  []asyncEntry
+	head     int
+	size     int
+	writing  bool
+	closed   bool
+	stopped  bool
+	drained  []chan struct{}
+
+	writeMu sync.Mutex
+	done    chan struct{}
+}
+
+// newAsyncWriter writes each batch to w in one write.
+func newAsyncWriter(cfg AsyncCfg, w io.Writer) *asyncWriter {
+	var buf []byte
+	return startAsyncWriter(cfg, func(batch []asyncEntry) error {
+		buf = buf[:0]
+		for _, entry := range batch {
+			buf = append(buf, entry.data...)
+		}
+		_, err := w.Write(buf)
+		if cap(buf) > maxPooledBufferSize {
+			buf = nil
+		}
+		return err
+	})
+}
+
+// newAsyncOutput writes the records of each batch to output one by one, e.g. for syslog.
+func newAsyncOutput(cfg AsyncCfg, output func(Level, []byte) error) *asyncWriter {
+	return startAsyncWriter(cfg, func(batch []asyncEntry) error {
+		var firstErr error
+		for _, entry := range batch {
+			err := output(entry.level, entry.data)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	})
+}
+
+func startAsyncWriter(cfg AsyncCfg, write func(batch []asyncEntry) error) *asyncWriter {
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultAsyncBufferSize
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultAsyncBatchSize
+	}
+	policy := strings.ToLower(cfg.OverflowPolicy)
+	if policy != dropNewestPolicy && policy != dropOldestPolicy {
+		policy = blockPolicy
+	}
+
+	aw := &asyncWriter{
+		policy:    policy,
+		batchSize: batchSize,
+		write:     write,
+		entries:   make([]asyncEntry, bufferSize),
+		done:      make(chan struct{}),
+	}
+	aw.notEmpty = sync.NewCond(&aw.mu)
+	aw.notFull = sync.NewCond(&aw.mu)
+	go aw.run()
+	return aw
+}
+
+// output queues a copy of p, p is reused by the handler once it returns.
+func (aw *asyncWriter) output(level Level, p []byte) error {
+	data := make([]byte, len(p))
+	copy(data, p)
+	entry := asyncEntry{level: level, data: data}
+
+	aw.mu.Lock()
+	for aw.size == len(aw.entries) && !aw.closed {
+		if aw.policy != blockPolicy {
+			break
+		}
+		aw.notFull.Wait()
+	}
+
+	if aw.closed {
+		aw.mu.Unlock()
+		return aw.writeBatch([]asyncEntry{entry})
+	}
+
+	if aw.size == len(aw.entries) {
+		if aw.policy == dropNewestPolicy || !aw.dropOldest(level) {
+			aw.mu.Unlock()
+			atomic.AddUint64(&aw.dropped, 1)
+			return nil
+		}
+		atomic.AddUint64(&aw.dropped, 1)
+	}
+
+	aw.entries[(aw.head+aw.size)%len(aw.entries)] = entry
+	aw.size++
+	aw.notEmpty.Signal()
+	aw.mu.Unlock()
+	return nil
+}
+
+// dropOldest removes the oldest queued record of the lowest level which is not higher than level.
+func (aw *asyncWriter) dropOldest(level Level) bool {
+	victim := -1
+	for i := 0; i < aw.size; i++ {
+		entryLevel := aw.entries[(aw.head+i)%len(aw.entries)].level
+		if entryLevel > level {
+			continue
+		}
+		if victim < 0 || entryLevel < aw.entries[(aw.head+victim)%len(aw.entries)].level {
+			victim = i
+		}
+	}
+	if victim < 0 {
+		return false
+	}
+	for i := victim; i > 0; i-- {
+		aw.entries[(aw.head+i)%len(aw.entries)] = aw.entries[(aw.head+i-1)%len(aw.entries)]
+	}
+	aw.entries[aw.head] = asyncEntry{}
+	aw.head = (aw.head + 1) % len(aw.entries)
+	aw.size--
+	return true
+}
+
+func (aw *asyncWriter) run() {
+	defer close(aw.done)
+	batch := make([]asyncEntry, 0, aw.batchSize)
+	for {
+		aw.mu.Lock()
+		for aw.size == 0 && !aw.stopped {
+			aw.notEmpty.Wait()
+		}
+		if aw.size == 0 {
+			aw.mu.Unlock()
+			return
+		}
+		batch = batch[:0]
+		for aw.size > 0 && len(batch) < aw.batchSize {
+			batch = append(batch, aw.entries[aw.head])
+			aw.entries[aw.head] = asyncEntry{}
+			aw.head = (aw.head + 1) % len(aw.entries)
+			aw.size--
+		}
+		aw.writing = true
+		aw.notFull.Broadcast()
+		aw.mu.Unlock()
+
+		err := aw.writeBatch(batch)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s]:: failed to write %d log entries : %v\n", PackageName, len(batch), err)
+		}
+
+		aw.mu.Lock()
+		aw.writing = false
+		if aw.size == 0 {
+			for _, drained := range aw.drained {
+				close(drained)
+			}
+			aw.drained = nil
+		}
+		aw.mu.Unlock()
+	}
+}
+
+// writeBatch serializes the writes of the worker and the synchronous writes after Close.
+func (aw *asyncWriter) writeBatch(batch []asyncEntry) error {
+	aw.writeMu.Lock()
+	defer aw.writeMu.Unlock()
+	return aw.write(batch)
+}
+
+// Flush waits until all the queued records are written or ctx is done.
+func (aw *asyncWriter) Flush(ctx context.Context) error {
+	aw.mu.Lock()
+	if aw.size == 0 && !aw.writing {
+		aw.mu.Unlock()
+		return nil
+	}
+	drained := make(chan struct{})
+	aw.drained = append(aw.drained, drained)
+	aw.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+	Close - stops queueing new records and drains the queued records until ctx is done. The records which are not
+			written before the deadline are dropped, Close still waits for the batch being written so that the
+			output can be closed by the caller. Records dropped after the last report of the logger are reported
+			to stderr because the logger can not be used any more.
+*/
+func (aw *asyncWriter) Close(ctx context.Context) error {
+	aw.mu.Lock()
+	aw.closed = true
+	aw.notFull.Broadcast()
+	aw.mu.Unlock()
+
+	err := aw.Flush(ctx)
+
+	aw.mu.Lock()
+	aw.stopped = true
+	if err != nil {
+		// records which could not be written before the deadline are counted as dropped
+		atomic.AddUint64(&aw.dropped, uint64(aw.size))
+		for i := 0; i < aw.size; i++ {
+			aw.entries[(aw.head+i)%len(aw.entries)] = asyncEntry{}
+		}
+		aw.size = 0
+	}
+	aw.notEmpty.Broadcast()
+	aw.mu.Unlock()
+
+	<-aw.done
+	dropped := aw.takeDropped()
+	if dropped > 0 {
+		fmt.Fprintf(os.Stderr, "[%s]:: dropped %d log entries on close\n", PackageName, dropped)
+	}
+	return err
+}
+
+// takeDropped returns the number of records dropped since the last call.
+func (aw *asyncWriter) takeDropped() uint64 {
+	return atomic.SwapUint64(&aw.dropped, 0)
+}
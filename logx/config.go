@@ -1,8 +1,72 @@
 package logx
 
+import "time"
+
+/*
+	LogCfg - LogRotation is the external rotation mode, the log file is reopened when it is renamed or removed by
+			 a tool such as logrotate. Built-in rotation of RotationSetting is used instead when it is configured.
+			 When Sinks are configured, the logs are written to the sinks and LogFilePath, LogFormat and LogBackend
+			 are not used.
+*/
 type LogCfg struct {
-	LogFilePath string `mapstructure:"log_file" json:"log_file"`
-	LogLevel    string `mapstructure:"log_level" json:"log_level"`
-	LogFormat   string `mapstructure:"log_format" json:"log_format"`
-	LogRotation bool   `mapstructure:"log_rotation" json:"log_rotation"`
+	LogFilePath     string      `mapstructure:"log_file" json:"log_file"`
+	LogLevel        string      `mapstructure:"log_level" json:"log_level"`
+	LogFormat       string      `mapstructure:"log_format" json:"log_format"`
+	LogRotation     bool        `mapstructure:"log_rotation" json:"log_rotation"`
+	LogBackend      string      `mapstructure:"log_backend" json:"log_backend"` // "logrus" (default) or "native", native backend writes JSON only
+	RotationSetting RotationCfg `mapstructure:"rotation_setting" json:"rotation_setting"`
+	Sinks           []SinkCfg   `mapstructure:"sinks" json:"sinks"`
+	AsyncSetting    AsyncCfg    `mapstructure:"async_setting" json:"async_setting"`
+}
+
+/*
+	AsyncCfg - logs are queued in a buffer of BufferSize records and written in batches of up to BatchSize records
+			   by a background goroutine, each sink has its own buffer. OverflowPolicy is applied when the buffer
+			   is full;
+				block (default) : the caller waits until the buffer has free space
+				drop_newest     : the new record is dropped
+				drop_oldest     : the oldest record of the lowest level, not higher than the new record, is
+								  dropped.
+			   The number of dropped records is logged at every DropReportInterval. Logger.Close should be called
+			   on shutdown to write the buffered records.
+	For example;
+		async_setting:
+			enabled: true
+			buffer_size: 4096
+			batch_size: 128
+			overflow_policy: drop_oldest
+			drop_report_interval: "30s"
+*/
+type AsyncCfg struct {
+	Enabled            bool          `mapstructure:"enabled" json:"enabled"`
+	BufferSize         int           `mapstructure:"buffer_size" json:"buffer_size"`
+	BatchSize          int           `mapstructure:"batch_size" json:"batch_size"`
+	OverflowPolicy     string        `mapstructure:"overflow_policy" json:"overflow_policy"`
+	DropReportInterval time.Duration `mapstructure:"drop_report_interval" json:"drop_report_interval"`
+}
+
+/*
+	RotationCfg - the log file is rotated when it reaches MaxSizeMB or at every Interval, whichever comes first.
+				  Rotated files are named with the rotation time, e.g. "app-2020-06-01T10-00-00.000.log", and are
+				  gzipped in background if Compress is set. Rotated files older than MaxAge or beyond the newest
+				  MaxBackups are removed, zero means no limit. Interval boundaries are aligned to local time, e.g.
+				  "24h" rotates at local midnight.
+	For example;
+		rotation_setting:
+			max_size_mb: 100
+			interval: "24h"
+			compress: true
+			max_age: "168h"
+			max_backups: 10
+*/
+type RotationCfg struct {
+	MaxSizeMB  int           `mapstructure:"max_size_mb" json:"max_size_mb"`
+	Interval   time.Duration `mapstructure:"interval" json:"interval"`
+	Compress   bool          `mapstructure:"compress" json:"compress"`
+	MaxAge     time.Duration `mapstructure:"max_age" json:"max_age"`
+	MaxBackups int           `mapstructure:"max_backups" json:"max_backups"`
+}
+
+func (cfg RotationCfg) enabled() bool {
+	return cfg.MaxSizeMB > 0 || cfg.Interval > 0
 }
@@ -0,0 +1,81 @@
+package logx
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const maxPooledBufferSize int = 64 << 10
+
+// recordEncoder appends a record to buf, keys are the sorted keys of fields.
+type recordEncoder func(buf []byte, t time.Time, level Level, msg string, keys []string, fields KV) []byte
+
+// recordBuffer keeps the buffer and the key slice of a record so that they are reused across records.
+type recordBuffer struct {
+	buf  []byte
+	keys []string
+}
+
+var recordBufferPool = sync.Pool{
+	New: func() interface{} {
+		return &recordBuffer{
+			buf:  make([]byte, 0, 1024),
+			keys: make([]string, 0, 16),
+		}
+	},
+}
+
+// encodingHandler is the base of the native handlers, records are encoded into pooled buffers and passed to output.
+type encodingHandler struct {
+	level  int32
+	encode recordEncoder
+	output func(Level, []byte) error
+}
+
+func newEncodingHandler(encode recordEncoder, output func(Level, []byte) error, level Level) *encodingHandler {
+	return &encodingHandler{
+		level:  int32(level),
+		encode: encode,
+		output: output,
+	}
+}
+
+func (h *encodingHandler) Enabled(level Level) bool {
+	return level >= Level(atomic.LoadInt32(&h.level))
+}
+
+func (h *encodingHandler) SetLevel(level Level) {
+	atomic.StoreInt32(&h.level, int32(level))
+}
+
+func (h *encodingHandler) Handle(ctx context.Context, level Level, msg string, fields KV) error {
+	rb := recordBufferPool.Get().(*recordBuffer)
+	rb.keys = rb.keys[:0]
+	for key := range fields {
+		rb.keys = append(rb.keys, key)
+	}
+	sort.Strings(rb.keys)
+	rb.buf = h.encode(rb.buf[:0], time.Now(), level, msg, rb.keys, fields)
+
+	err := h.output(level, rb.buf)
+
+	if cap(rb.buf) <= maxPooledBufferSize {
+		recordBufferPool.Put(rb)
+	}
+	return err
+}
+
+// writerOutput serializes the writes, so that a record is written in one piece.
+func writerOutput(w io.Writer) func(Level, []byte) error {
+	var mu sync.Mutex
+	return func(_ Level, p []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := w.Write(p)
+		return err
+	}
+}
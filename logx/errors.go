@@ -0,0 +1,13 @@
+package logx
+
+import "github.com/kyawmyintthein/orange-contrib/errorx"
+
+type InvalidSinkError struct {
+	*errorx.ErrorX
+}
+
+func NewInvalidSinkError(sink string, reason string) *InvalidSinkError {
+	return &InvalidSinkError{
+		errorx.NewErrorX("[%s] invalid sink '%s' : %s", PackageName, sink, reason),
+	}
+}
@@ -0,0 +1,82 @@
+package logx
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+)
+
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+func (level Level) String() string {
+	switch level {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warning"
+	case ErrorLevel:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLevel accepts the level names of logrus, e.g. "warn" and "warning".
+func ParseLevel(level string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug", "trace":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error", "fatal", "panic":
+		return ErrorLevel, nil
+	}
+	return InfoLevel, errorx.NewErrorX("[%s] invalid log level '%s'", PackageName, level)
+}
+
+/*
+	Handler - is the backend of Logger. Logger builds the message and the fields (error fields, KV and fields of the
+			  request log entry) and passes them to Handle, so that the backend can be swapped without touching
+			  the call sites. Enabled is checked before the message and the fields are built.
+*/
+type Handler interface {
+	Enabled(Level) bool
+	Handle(ctx context.Context, level Level, msg string, fields KV) error
+	SetLevel(Level)
+}
+
+// switchableWriter lets the log file be replaced, e.g. by rotation, while handlers keep writing to the same writer.
+type switchableWriter struct {
+	mu sync.RWMutex
+	w  io.Writer
+}
+
+func newSwitchableWriter(w io.Writer) *switchableWriter {
+	return &switchableWriter{w: w}
+}
+
+func (sw *switchableWriter) Write(p []byte) (int, error) {
+	sw.mu.RLock()
+	defer sw.mu.RUnlock()
+	return sw.w.Write(p)
+}
+
+func (sw *switchableWriter) Switch(w io.Writer) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	sw.w = w
+}
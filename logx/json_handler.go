@@ -0,0 +1,203 @@
+package logx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	hexDigits string = "0123456789abcdef"
+)
+
+/*
+	NewJSONHandler - is the native backend which writes a line of JSON per record without going through logrus.
+					 The output has the same "time", "level" and "msg" keys as logrus JSON formatter, fields are
+					 sorted by key. Buffers are pooled, so a record is encoded without allocation for the common
+					 field types (string, numbers, bool, time, duration and error).
+*/
+func NewJSONHandler(w io.Writer, level Level) Handler {
+	return newEncodingHandler(encodeJSON, writerOutput(w), level)
+}
+
+func encodeJSON(buf []byte, t time.Time, level Level, msg string, keys []string, fields KV) []byte {
+	buf = append(buf, `{"time":"`...)
+	buf = t.AppendFormat(buf, time.RFC3339Nano)
+	buf = append(buf, `","level":"`...)
+	buf = append(buf, level.String()...)
+	buf = append(buf, `","msg":`...)
+	buf = appendJSONString(buf, msg)
+
+	for _, key := range keys {
+		buf = append(buf, ',')
+		// the same as logrus, fields do not override the standard keys
+		if key == "time" || key == "level" || key == "msg" {
+			buf = appendJSONString(buf, "fields."+key)
+		} else {
+			buf = appendJSONString(buf, key)
+		}
+		buf = append(buf, ':')
+		buf = appendJSONValue(buf, fields[key])
+	}
+	return append(buf, '}', '\n')
+}
+
+func appendJSONValue(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, "null"...)
+	case string:
+		return appendJSONString(buf, v)
+	case bool:
+		return strconv.AppendBool(buf, v)
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case int8:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(buf, v, 10)
+	case uint:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case uint8:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(buf, v, 10)
+	case float32:
+		return appendJSONFloat(buf, float64(v), 32)
+	case float64:
+		return appendJSONFloat(buf, v, 64)
+	case time.Time:
+		buf = append(buf, '"')
+		buf = v.AppendFormat(buf, time.RFC3339Nano)
+		return append(buf, '"')
+	case time.Duration:
+		return appendJSONString(buf, v.String())
+	case error:
+		return appendJSONString(buf, v.Error())
+	case KV:
+		return appendJSONObject(buf, v)
+	case map[string]interface{}:
+		return appendJSONObject(buf, v)
+	case []KV:
+		buf = append(buf, '[')
+		for i, item := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONObject(buf, item)
+		}
+		return append(buf, ']')
+	case []interface{}:
+		buf = append(buf, '[')
+		for i, item := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONValue(buf, item)
+		}
+		return append(buf, ']')
+	case []string:
+		buf = append(buf, '[')
+		for i, item := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONString(buf, item)
+		}
+		return append(buf, ']')
+	case json.Marshaler:
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return appendJSONString(buf, fmt.Sprintf("%v", v))
+		}
+		return append(buf, data...)
+	case fmt.Stringer:
+		return appendJSONString(buf, v.String())
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return appendJSONString(buf, fmt.Sprintf("%v", v))
+		}
+		return append(buf, data...)
+	}
+}
+
+func appendJSONObject(buf []byte, m map[string]interface{}) []byte {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	buf = append(buf, '{')
+	for i, key := range keys {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendJSONString(buf, key)
+		buf = append(buf, ':')
+		buf = appendJSONValue(buf, m[key])
+	}
+	return append(buf, '}')
+}
+
+// appendJSONFloat writes NaN and infinity as string because JSON has no representation of them.
+func appendJSONFloat(buf []byte, f float64, bitSize int) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return appendJSONString(buf, strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+	return strconv.AppendFloat(buf, f, 'f', -1, bitSize)
+}
+
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				buf = append(buf, '\\', b)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, `�`...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
@@ -2,13 +2,19 @@ package logx
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/go-chi/chi/middleware"
 	"github.com/kyawmyintthein/orange-contrib/errorx"
+	"github.com/kyawmyintthein/orange-contrib/optionx"
 	"github.com/sirupsen/logrus"
 )
 
@@ -37,19 +43,34 @@ type Logger interface {
 
 	SetLogLevel(string) error
 	NewRequestLogger() func(next http.Handler) http.Handler
+
+	// Flush waits until the buffered logs of async mode are written or ctx is done.
+	Flush(context.Context) error
+	// Close writes the buffered logs until ctx is done and closes the log files, it should be called on shutdown.
+	Close(context.Context) error
 }
 
 type logger struct {
 	cfg     *LogCfg
-	logrus  *logrus.Logger
-	logfile *os.File
-	absPath string
+	handler Handler
+	writer  *switchableWriter
+	logfile io.WriteCloser
+	closers []io.Closer
+	async   []*asyncWriter
+
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 const (
 	jsonLogFormat   = "json"
 	textLogFormat   = "text"
-	defaultLogLevel = logrus.InfoLevel
+	defaultLogLevel = InfoLevel
+
+	logrusBackend = "logrus"
+	nativeBackend = "native"
+
+	defaultDropReportInterval = 10 * time.Second
 )
 
 var (
@@ -61,119 +82,281 @@ var (
 	_stdLogger Logger
 )
 
-func Init(cfg *LogCfg) Logger {
+func Init(cfg *LogCfg, opts ...optionx.Option) Logger {
 	if _stdLogger == nil {
-		_stdLogger = new(cfg)
+		_stdLogger = new(cfg, opts...)
 	}
 	return _stdLogger
 }
 
-func New(cfg *LogCfg) Logger {
-	return new(cfg)
+func New(cfg *LogCfg, opts ...optionx.Option) Logger {
+	return new(cfg, opts...)
 }
 
-func new(cfg *LogCfg) Logger {
+func new(cfg *LogCfg, opts ...optionx.Option) Logger {
+	options := optionx.NewOptions(opts...)
 	logger := &logger{
-		cfg: cfg,
+		cfg:    cfg,
+		writer: newSwitchableWriter(os.Stdout),
+		done:   make(chan struct{}),
+	}
+
+	handler, ok := options.Context.Value(handlerKey{}).(Handler)
+	if handler != nil && ok {
+		// the handler owns its output, log file and rotation of the setting are not used
+		logger.handler = handler
+		return logger
 	}
-	logger.newLogrus()
 
+	logger.newHandler()
 	go logger.watchLogRotation()
+	if len(logger.async) > 0 {
+		go logger.reportDropped()
+	}
 
 	return logger
 }
 
-// create new logrus object
-func (logger *logger) newLogrus() {
-	logger.logrus = &logrus.Logger{
-		Hooks: make(logrus.LevelHooks),
-	}
-
-	logLevel, err := logrus.ParseLevel(logger.cfg.LogLevel)
+// create handler of the configured backend, logrus is the default backend
+func (logger *logger) newHandler() {
+	logLevel, err := ParseLevel(logger.cfg.LogLevel)
 	if err != nil {
 		logLevel = defaultLogLevel
 	}
-	logger.logrus.Level = logLevel
 
-	switch logger.cfg.LogFormat {
-	case jsonLogFormat:
-		logger.logrus.SetFormatter(&logrus.JSONFormatter{})
+	if len(logger.cfg.Sinks) > 0 {
+		logger.newSinkHandler(logLevel)
+		return
+	}
+
+	var async *asyncWriter
+	if logger.cfg.AsyncSetting.Enabled {
+		// the async writer writes into the switchable writer, so that the log file can still be replaced
+		async = newAsyncWriter(logger.cfg.AsyncSetting, logger.writer)
+		logger.async = append(logger.async, async)
+	}
+
+	switch logger.cfg.LogBackend {
+	case nativeBackend:
+		if async != nil {
+			logger.handler = newEncodingHandler(encodeJSON, async.output, logLevel)
+		} else {
+			logger.handler = NewJSONHandler(logger.writer, logLevel)
+		}
 	default:
-		logger.logrus.SetFormatter(&logrus.TextFormatter{})
+		logrusLogger := &logrus.Logger{
+			Out:   logger.writer,
+			Hooks: make(logrus.LevelHooks),
+			Level: toLogrusLevel(logLevel),
+		}
+		var formatter logrus.Formatter
+		switch logger.cfg.LogFormat {
+		case jsonLogFormat:
+			formatter = &logrus.JSONFormatter{}
+		default:
+			formatter = &logrus.TextFormatter{}
+		}
+		if async != nil {
+			// records are queued with their level by the formatter, so that drop_oldest policy can compare them
+			logrusLogger.Out = ioutil.Discard
+			formatter = &levelOutputFormatter{Formatter: formatter, output: async.output}
+		}
+		logrusLogger.SetFormatter(formatter)
+		logger.handler = NewLogrusHandler(logrusLogger)
 	}
 
+	ctx := context.Background()
 	if logger.cfg.LogFilePath == "" {
-		logger.logrus.Out = os.Stdout
-		logger.logrus.Errorf("[%s]:: empty log file. Set 'Stdout' as default \n", PackageName)
-		logger.logrus.Infof("[%s]:: initialized logx successfully \n", PackageName)
+		logger.log(ctx, ErrorLevel, nil, nil, fmt.Sprintf("[%s]:: empty log file. Set 'Stdout' as default \n", PackageName))
+		logger.Infof(ctx, "[%s]:: initialized logx successfully \n", PackageName)
 		return
 	}
 
-	logfile, err := os.OpenFile(logger.cfg.LogFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0755)
+	var logfile io.WriteCloser
+	if logger.cfg.RotationSetting.enabled() {
+		logfile, err = newRotatingFile(logger.cfg.LogFilePath, logger.cfg.RotationSetting)
+	} else {
+		logfile, err = os.OpenFile(logger.cfg.LogFilePath, _defaultFileFlag, _defaultFileMode)
+	}
 	if err != nil {
-		logger.logrus.Errorln("[%s]:: failed to set log file. Error : '%v'. Set 'Stdout' as default", PackageName, err)
+		logger.Errorf(ctx, err, "[%s]:: failed to set log file. Error : '%v'. Set 'Stdout' as default", PackageName, err)
 		return
 	}
 
 	logger.logfile = logfile
-	logger.logrus.Out = logger.logfile
+	logger.writer.Switch(logfile)
 
-	logger.logrus.Infof("[%s]:: initialized logx successfully", PackageName)
+	logger.Infof(ctx, "[%s]:: initialized logx successfully", PackageName)
 }
 
+// newSinkHandler writes to all the configured sinks, invalid sinks are skipped and stdout is used if none is valid.
+func (logger *logger) newSinkHandler(logLevel Level) {
+	ctx := context.Background()
+	handlers := make([]Handler, 0, len(logger.cfg.Sinks))
+	var sinkErrs []error
+	for _, sinkCfg := range logger.cfg.Sinks {
+		s, err := newSink(sinkCfg, logLevel, logger.cfg.AsyncSetting)
+		if err != nil {
+			sinkErrs = append(sinkErrs, err)
+			continue
+		}
+		handlers = append(handlers, s.handler)
+		if s.async != nil {
+			logger.async = append(logger.async, s.async)
+		}
+		if s.closer != nil {
+			logger.closers = append(logger.closers, s.closer)
+		}
+	}
+
+	if len(handlers) == 0 {
+		logger.handler = NewTextHandler(logger.writer, logLevel)
+	} else {
+		logger.handler = NewMultiHandler(handlers...)
+	}
+
+	for _, err := range sinkErrs {
+		logger.Errorf(ctx, err, "[%s]:: failed to set log sink. Error : '%v'", PackageName, err)
+	}
+	if len(handlers) == 0 {
+		logger.log(ctx, ErrorLevel, nil, nil, fmt.Sprintf("[%s]:: no valid log sink. Set 'Stdout' as default", PackageName))
+	}
+	logger.Infof(ctx, "[%s]:: initialized logx successfully with %d sinks", PackageName, len(handlers))
+}
+
+// watchLogRotation reopens the log file when it is renamed or removed by external rotation tool.
 func (logger *logger) watchLogRotation() {
-	if !logger.cfg.LogRotation {
-		logger.Infof(context.Background(), "[%s]:: disabled log rotation", PackageName)
+	ctx := context.Background()
+	if !logger.cfg.LogRotation || logger.cfg.RotationSetting.enabled() || len(logger.cfg.Sinks) > 0 {
+		logger.Infof(ctx, "[%s]:: disabled log rotation", PackageName)
+		return
+	}
+	if logger.logfile == nil {
+		logger.Warnf(ctx, "[%s]:: log rotation is ignored, logs are not written to file", PackageName)
 		return
 	}
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		panic(err)
-	}
-
-	// Process events
-	go func() {
-		for {
-			select {
-			case ev := <-watcher.Events:
-				if ev.Name == logger.absPath && (ev.Op.String() == "REMOVE" || ev.Op.String() == "RENAME") {
-					logger.logfile.Close()
-					f, err := os.OpenFile(logger.cfg.LogFilePath, _defaultFileFlag, _defaultFileMode)
-					if err != nil {
-						panic(err)
-					}
-
-					logger.logrus.Out = f
-					logger.logfile = f
-
-					err = watcher.Add(logger.logfile.Name())
-					if err != nil {
-						panic(err)
-					}
-				}
-			case err := <-watcher.Errors:
-				logger.Errorf(context.Background(), err, "[%s]:: failed to watch log file", PackageName)
+		logger.Errorf(ctx, err, "[%s]:: failed to watch log file, log rotation is disabled", PackageName)
+		return
+	}
+	path := filepath.Clean(logger.cfg.LogFilePath)
+	err = watcher.Add(path)
+	if err != nil {
+		watcher.Close()
+		logger.Errorf(ctx, err, "[%s]:: failed to watch log file, log rotation is disabled", PackageName)
+		return
+	}
+
+	for {
+		select {
+		case <-logger.done:
+			watcher.Close()
+			return
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return
 			}
-		}
-	}()
+			if filepath.Clean(ev.Name) != path || ev.Op&(fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			f, err := os.OpenFile(path, _defaultFileFlag, _defaultFileMode)
+			if err != nil {
+				// keep writing into the renamed file, it is retried on the next event
+				logger.Errorf(ctx, err, "[%s]:: failed to reopen log file", PackageName)
+				continue
+			}
+			logger.writer.Switch(f)
+			logger.logfile.Close()
+			logger.logfile = f
 
-	err = watcher.Add(logger.cfg.LogFilePath)
-	if err != nil {
-		panic(err)
+			err = watcher.Add(path)
+			if err != nil {
+				logger.Errorf(ctx, err, "[%s]:: failed to watch reopened log file", PackageName)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Errorf(ctx, err, "[%s]:: failed to watch log file", PackageName)
+		}
 	}
 }
 
 func (logger *logger) SetLogLevel(level string) error {
-	logLevel, err := logrus.ParseLevel(level)
+	logLevel, err := ParseLevel(level)
 	if err != nil {
-		logLevel = logrus.InfoLevel
 		return err
 	}
-	logger.logrus.Level = logLevel
+	logger.handler.SetLevel(logLevel)
+	return nil
+}
+
+// reportDropped logs the number of records dropped by the async writers at every drop report interval.
+func (logger *logger) reportDropped() {
+	interval := logger.cfg.AsyncSetting.DropReportInterval
+	if interval <= 0 {
+		interval = defaultDropReportInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-logger.done:
+			return
+		case <-ticker.C:
+			logger.logDropped(context.Background())
+		}
+	}
+}
+
+func (logger *logger) logDropped(ctx context.Context) {
+	var dropped uint64
+	for _, async := range logger.async {
+		dropped += async.takeDropped()
+	}
+	if dropped > 0 {
+		logger.WarnKVf(ctx, KV{"dropped_entries": dropped}, "[%s]:: dropped %d log entries, async buffer is full", PackageName, dropped)
+	}
+}
+
+func (logger *logger) Flush(ctx context.Context) error {
+	for _, async := range logger.async {
+		err := async.Flush(ctx)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func (logger *logger) Close(ctx context.Context) error {
+	var firstErr error
+	logger.closeOnce.Do(func() {
+		close(logger.done)
+		// the records dropped since the last report are logged before the buffers are drained
+		logger.logDropped(ctx)
+		for _, async := range logger.async {
+			err := async.Close(ctx)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		closers := logger.closers
+		if logger.logfile != nil {
+			closers = append(closers, logger.logfile)
+		}
+		for _, closer := range closers {
+			err := closer.Close()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	})
+	return firstErr
+}
+
 func getLogger() Logger {
 	if _stdLogger == nil {
 		return new(&LogCfg{})
@@ -181,170 +364,154 @@ func getLogger() Logger {
 	return _stdLogger
 }
 
+/*
+	log - is the single path of all the logging methods. Fields of the request log entry in ctx, error fields and kv
+		  are merged in this order, the later ones win for the same key.
+*/
+func (l *logger) log(ctx context.Context, level Level, err error, kv KV, msg string) {
+	entry := l.getStructuredLogEntry(ctx)
+	handler := l.handler
+	if entry != nil {
+		handler = entry.handler
+	}
+	handler.Handle(ctx, level, msg, mergeFields(entry, err, kv))
+}
+
+func mergeFields(entry *StructuredLoggerEntry, err error, kv KV) KV {
+	size := len(kv)
+	if entry != nil {
+		size += len(entry.fields) + 1
+	}
+	fields := make(KV, size)
+	if entry != nil {
+		for k, v := range entry.fields {
+			fields[k] = v
+		}
+		fields["@timestamp"] = time.Now().Format(time.RFC3339Nano)
+	}
+	if err != nil {
+		for k, v := range getErrorFields(err) {
+			fields[k] = v
+		}
+	}
+	for k, v := range kv {
+		fields[k] = v
+	}
+	return fields
+}
+
+func (l *logger) enabled(ctx context.Context, level Level) bool {
+	entry := l.getStructuredLogEntry(ctx)
+	if entry != nil {
+		return entry.handler.Enabled(level)
+	}
+	return l.handler.Enabled(level)
+}
+
 func (l *logger) Error(ctx context.Context, err error, args ...interface{}) {
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(getErrorFields(err)).Errorln(args...)
-		return
+	if l.enabled(ctx, ErrorLevel) {
+		l.log(ctx, ErrorLevel, err, nil, sprintln(args...))
 	}
-	l.logrus.WithFields(getErrorFields(err)).Errorln(args...)
 }
 
 func (l *logger) Warn(ctx context.Context, args ...interface{}) {
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.Warnln(args...)
-		return
+	if l.enabled(ctx, WarnLevel) {
+		l.log(ctx, WarnLevel, nil, nil, sprintln(args...))
 	}
-	l.logrus.Warnln(args...)
 }
 
 func (l *logger) Info(ctx context.Context, args ...interface{}) {
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.Infoln(args...)
-		return
+	if l.enabled(ctx, InfoLevel) {
+		l.log(ctx, InfoLevel, nil, nil, sprintln(args...))
 	}
-	l.logrus.Infoln(args...)
 }
 
 func (l *logger) Debug(ctx context.Context, args ...interface{}) {
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.Debugln(args...)
-		return
+	if l.enabled(ctx, DebugLevel) {
+		l.log(ctx, DebugLevel, nil, nil, sprintln(args...))
 	}
-	l.logrus.Debugln(args...)
 }
 
 func (l *logger) Errorf(ctx context.Context, err error, message string, args ...interface{}) {
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(getErrorFields(err)).Errorf(message, args...)
-		return
+	if l.enabled(ctx, ErrorLevel) {
+		l.log(ctx, ErrorLevel, err, nil, fmt.Sprintf(message, args...))
 	}
-	l.logrus.WithFields(getErrorFields(err)).Errorf(message, args...)
 }
 
 func (l *logger) Warnf(ctx context.Context, message string, args ...interface{}) {
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.Warnf(message, args...)
-		return
+	if l.enabled(ctx, WarnLevel) {
+		l.log(ctx, WarnLevel, nil, nil, fmt.Sprintf(message, args...))
 	}
-	l.logrus.Warnf(message, args...)
 }
 
 func (l *logger) Infof(ctx context.Context, message string, args ...interface{}) {
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.Infof(message, args...)
-		return
+	if l.enabled(ctx, InfoLevel) {
+		l.log(ctx, InfoLevel, nil, nil, fmt.Sprintf(message, args...))
 	}
-	l.logrus.Infof(message, args...)
 }
 
 func (l *logger) Debugf(ctx context.Context, message string, args ...interface{}) {
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.Debugf(message, args...)
-		return
+	if l.enabled(ctx, DebugLevel) {
+		l.log(ctx, DebugLevel, nil, nil, fmt.Sprintf(message, args...))
 	}
-	l.logrus.Debugf(message, args...)
 }
 
 func (l *logger) ErrorKV(ctx context.Context, err error, kv KV, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	errorFields := getErrorFields(err)
-	for k, v := range errorFields {
-		fields[k] = v
+	if l.enabled(ctx, ErrorLevel) {
+		l.log(ctx, ErrorLevel, err, kv, sprintln(args...))
 	}
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(fields).Errorln(args...)
-		return
-	}
-	l.logrus.WithFields(fields).Errorln(args...)
 }
 
 func (l *logger) WarnKV(ctx context.Context, kv KV, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(fields).Warnln(args...)
+	if l.enabled(ctx, WarnLevel) {
+		l.log(ctx, WarnLevel, nil, kv, sprintln(args...))
 	}
-	l.logrus.WithFields(fields).Warnln(args...)
 }
 
 func (l *logger) InfoKV(ctx context.Context, kv KV, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(fields).Info(args...)
+	if l.enabled(ctx, InfoLevel) {
+		l.log(ctx, InfoLevel, nil, kv, sprintln(args...))
 	}
-	l.logrus.WithFields(fields).Info(args...)
 }
 
 func (l *logger) DebugKV(ctx context.Context, kv KV, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(fields).Debug(args...)
-		return
+	if l.enabled(ctx, DebugLevel) {
+		l.log(ctx, DebugLevel, nil, kv, sprintln(args...))
 	}
-	l.logrus.WithFields(fields).Debug(args...)
 }
 
 func (l *logger) ErrorKVf(ctx context.Context, err error, kv KV, message string, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	errorFields := getErrorFields(err)
-	for k, v := range errorFields {
-		fields[k] = v
+	if l.enabled(ctx, ErrorLevel) {
+		l.log(ctx, ErrorLevel, err, kv, fmt.Sprintf(message, args...))
 	}
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(fields).Errorf(message, args...)
-		return
-	}
-	l.logrus.WithFields(fields).Errorf(message, args...)
 }
 
 func (l *logger) WarnKVf(ctx context.Context, kv KV, message string, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(fields).Warnf(message, args...)
-		return
+	if l.enabled(ctx, WarnLevel) {
+		l.log(ctx, WarnLevel, nil, kv, fmt.Sprintf(message, args...))
 	}
-	l.logrus.WithFields(fields).Warnf(message, args...)
 }
 
 func (l *logger) InfoKVf(ctx context.Context, kv KV, message string, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(fields).Infof(message, args...)
-		return
+	if l.enabled(ctx, InfoLevel) {
+		l.log(ctx, InfoLevel, nil, kv, fmt.Sprintf(message, args...))
 	}
-	l.logrus.WithFields(fields).Infof(message, args...)
 }
 
 func (l *logger) DebugKVf(ctx context.Context, kv KV, message string, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	log := l.getStructuredLogEntry(ctx)
-	if log != nil {
-		log.logger.WithFields(fields).Debugf(message, args...)
-		return
+	if l.enabled(ctx, DebugLevel) {
+		l.log(ctx, DebugLevel, nil, kv, fmt.Sprintf(message, args...))
 	}
-	l.logrus.WithFields(fields).Debugf(message, args...)
 }
 
-// Implementation of ErrorLogger interface{} with clerrors custom error
-func getErrorFields(err error) logrus.Fields {
-	type causer interface {
-		Cause() error
-	}
+// sprintln formats the same as logrus Errorln, Warnln etc. which always add spaces between operands
+func sprintln(args ...interface{}) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
 
+// Implementation of ErrorLogger interface{} with clerrors custom error
+func getErrorFields(err error) KV {
 	var stacks interface{}
 	var rootCause interface{}
 	errCode := 0
@@ -352,38 +519,48 @@ func getErrorFields(err error) logrus.Fields {
 	statusCode := 0
 	errMsg := err.Error()
 	messages := ""
-	errStacktrace, ok := err.(errorx.StackTracer)
-	if ok {
-		stacks = errStacktrace.GetStackAsJSON()
-	}
 
-	errWithCode, ok := err.(errorx.ErrorCode)
-	if ok {
-		errCode = errWithCode.Code()
-	}
+	// the first value found in the chain is used, the chain may mix errorx errors and fmt.Errorf("%w") wrappers
+	errorx.Walk(err, func(e error) bool {
+		errStacktrace, ok := e.(errorx.StackTracer)
+		if ok && stacks == nil {
+			stacks = errStacktrace.GetStackAsJSON()
+		}
 
-	errWithName, ok := err.(errorx.ErrorID)
-	if ok {
-		errTitle = errWithName.ID()
-	}
+		errWithCode, ok := e.(errorx.ErrorCode)
+		if ok && errCode == 0 {
+			errCode = errWithCode.Code()
+		}
 
-	httpError, ok := err.(errorx.HttpError)
-	if ok {
-		statusCode = httpError.StatusCode()
-	}
+		errWithName, ok := e.(errorx.ErrorID)
+		if ok && errTitle == "" {
+			errTitle = errWithName.ID()
+		}
+
+		httpError, ok := e.(errorx.HttpError)
+		if ok && statusCode == 0 {
+			statusCode = httpError.StatusCode()
+		}
+		return true
+	})
 
 	errorFormatter, ok := err.(errorx.ErrorFormatter)
 	if ok {
 		errMsg = errorFormatter.FormattedMessage()
 	}
 
-	errorCauser, ok := err.(causer)
-	if ok {
-		rootCause = errorCauser.Cause()
+	cause := errorx.Cause(err)
+	if cause != err {
+		rootCause = cause
+	}
+
+	var errs []KV
+	for _, item := range errorx.GetFieldErrors(err) {
+		errs = append(errs, getMultiErrorItemFields(item))
 	}
 
 	messages = errorx.GetErrorMessages(err)
-	fields := logrus.Fields{
+	fields := KV{
 		"error_message": errMsg,
 		"root_cause":    rootCause,
 		"stacktrace":    stacks,
@@ -402,13 +579,40 @@ func getErrorFields(err error) logrus.Fields {
 		fields["error_title"] = errTitle
 	}
 
+	if len(errs) > 0 {
+		fields["errors"] = errs
+	}
+
+	// attributes do not override the standard fields
+	for _, attr := range errorx.AllAttributes(err) {
+		_, exists := fields[attr.Key]
+		if !exists {
+			fields[attr.Key] = attr.Value
+		}
+	}
+
 	return fields
 }
 
-func convertKvsToLogrusFields(kv KV) logrus.Fields {
-	fields := make(logrus.Fields)
-	for k, v := range kv {
-		fields[k] = v
+func getMultiErrorItemFields(err error) KV {
+	fields := KV{
+		"error_message": err.Error(),
+	}
+	errorFormatter, ok := err.(errorx.ErrorFormatter)
+	if ok {
+		fields["error_message"] = errorFormatter.FormattedMessage()
+	}
+	errorField, ok := err.(errorx.ErrorField)
+	if ok {
+		fields["field"] = errorField.Field()
+	}
+	errWithCode, ok := err.(errorx.ErrorCode)
+	if ok && errWithCode.Code() != 0 {
+		fields["error_code"] = errWithCode.Code()
+	}
+	errWithID, ok := err.(errorx.ErrorID)
+	if ok && errWithID.ID() != "" {
+		fields["error_title"] = errWithID.ID()
 	}
 	return fields
 }
@@ -418,15 +622,13 @@ func (logger *logger) getStructuredLogEntry(ctx context.Context) *StructuredLogg
 	if log == nil {
 		log = ctx.Value(middleware.LogEntryCtxKey.String())
 	}
-	if log != nil {
-		logEntry := log.(*StructuredLoggerEntry)
-		logEntry.logger = logEntry.logger.WithField("@timestamp", time.Now().Format(time.RFC3339Nano))
+	logEntry, ok := log.(*StructuredLoggerEntry)
+	if ok {
 		return logEntry
 	}
-
 	return nil
 }
 
 func (logger *logger) NewRequestLogger() func(next http.Handler) http.Handler {
-	return middleware.RequestLogger(&RequestStructureLogger{logger.logrus})
+	return middleware.RequestLogger(&RequestStructureLogger{logger.handler})
 }
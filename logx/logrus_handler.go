@@ -0,0 +1,78 @@
+package logx
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+type logrusHandler struct {
+	logrus *logrus.Logger
+}
+
+// NewLogrusHandler adapts logrus logger to Handler, formatter, hooks and output of the logger are kept as they are.
+func NewLogrusHandler(l *logrus.Logger) Handler {
+	return &logrusHandler{logrus: l}
+}
+
+func (h *logrusHandler) Enabled(level Level) bool {
+	return h.logrus.IsLevelEnabled(toLogrusLevel(level))
+}
+
+func (h *logrusHandler) Handle(ctx context.Context, level Level, msg string, fields KV) error {
+	h.logrus.WithContext(ctx).WithFields(logrus.Fields(fields)).Log(toLogrusLevel(level), msg)
+	return nil
+}
+
+func (h *logrusHandler) SetLevel(level Level) {
+	h.logrus.SetLevel(toLogrusLevel(level))
+}
+
+func toLogrusLevel(level Level) logrus.Level {
+	switch level {
+	case DebugLevel:
+		return logrus.DebugLevel
+	case WarnLevel:
+		return logrus.WarnLevel
+	case ErrorLevel:
+		return logrus.ErrorLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+func fromLogrusLevel(level logrus.Level) Level {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		return ErrorLevel
+	case logrus.WarnLevel:
+		return WarnLevel
+	case logrus.DebugLevel, logrus.TraceLevel:
+		return DebugLevel
+	default:
+		return InfoLevel
+	}
+}
+
+/*
+	levelOutputFormatter - writes each formatted record to output with the level of the entry, so that async writer
+						   knows the levels of logrus records. Output of the logrus logger is not used.
+*/
+type levelOutputFormatter struct {
+	logrus.Formatter
+	output func(Level, []byte) error
+}
+
+func (f *levelOutputFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data, err := f.Formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	err = f.output(fromLogrusLevel(entry.Level), data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[%s]:: failed to write log entry : %v\n", PackageName, err)
+	}
+	return nil, nil
+}
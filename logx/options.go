@@ -0,0 +1,22 @@
+package logx
+
+import (
+	"context"
+
+	"github.com/kyawmyintthein/orange-contrib/optionx"
+)
+
+/*
+	WithHandler - replaces the backend of the logger, e.g. logx.New(cfg, logx.WithHandler(logx.NewJSONHandler(os.Stdout, logx.InfoLevel))).
+				  Log file, format and rotation of LogCfg are not used with custom handler.
+*/
+type handlerKey struct{}
+
+func WithHandler(handler Handler) optionx.Option {
+	return func(o *optionx.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, handlerKey{}, handler)
+	}
+}
@@ -0,0 +1,305 @@
+package logx
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat  string = "2006-01-02T15-04-05.000"
+	compressSuffix    string = ".gz"
+	compressTmpSuffix string = compressSuffix + ".tmp"
+	megabyte          int64  = 1024 * 1024
+)
+
+/*
+	rotatingFile - is the writer of built-in rotation. Rotation is checked on each write, compression and retention
+				   run in a background goroutine so that writes are not blocked by them. Errors are reported to stderr
+				   because the logger itself can not be used while its output is being rotated.
+*/
+type rotatingFile struct {
+	path string
+	cfg  RotationCfg
+
+	mu         sync.Mutex
+	file       *os.File
+	size       int64
+	nextRotate time.Time
+
+	mill      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newRotatingFile(path string, cfg RotationCfg) (*rotatingFile, error) {
+	rf := &rotatingFile{
+		path: path,
+		cfg:  cfg,
+		mill: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	err := rf.open()
+	if err != nil {
+		return nil, err
+	}
+	go rf.runMill()
+	// backups left by the previous process are compressed and cleaned up as well
+	rf.triggerMill()
+	return rf, nil
+}
+
+func (rf *rotatingFile) open() error {
+	err := os.MkdirAll(filepath.Dir(rf.path), 0755)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(rf.path, _defaultFileFlag, _defaultFileMode)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	rf.file = file
+	rf.size = info.Size()
+	if rf.cfg.Interval > 0 {
+		rf.nextRotate = nextRotation(time.Now(), rf.cfg.Interval)
+	}
+	return nil
+}
+
+// nextRotation returns the next interval boundary in local time, e.g. the local midnight for 24h interval.
+func nextRotation(now time.Time, interval time.Duration) time.Time {
+	_, offset := now.Zone()
+	shift := time.Duration(offset) * time.Second
+	return now.Add(shift).Truncate(interval).Add(interval).Add(-shift)
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+	if rf.shouldRotate(int64(len(p))) {
+		err := rf.rotate()
+		if err != nil {
+			// keep writing into the current file rather than losing the logs
+			fmt.Fprintf(os.Stderr, "[%s]:: failed to rotate log file '%s' : %v\n", PackageName, rf.path, err)
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *rotatingFile) shouldRotate(writeSize int64) bool {
+	if rf.size == 0 {
+		return false
+	}
+	if rf.cfg.MaxSizeMB > 0 && rf.size+writeSize > int64(rf.cfg.MaxSizeMB)*megabyte {
+		return true
+	}
+	return rf.cfg.Interval > 0 && !time.Now().Before(rf.nextRotate)
+}
+
+func (rf *rotatingFile) rotate() error {
+	err := rf.file.Close()
+	if err != nil {
+		return err
+	}
+	rf.file = nil
+
+	renameErr := os.Rename(rf.path, rf.backupName(time.Now()))
+	err = rf.open()
+	if err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return renameErr
+	}
+	rf.triggerMill()
+	return nil
+}
+
+func (rf *rotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := rf.nameParts()
+	return filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+}
+
+func (rf *rotatingFile) nameParts() (string, string, string) {
+	dir := filepath.Dir(rf.path)
+	base := filepath.Base(rf.path)
+	ext := filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+func (rf *rotatingFile) Close() error {
+	var err error
+	rf.closeOnce.Do(func() {
+		close(rf.done)
+		rf.mu.Lock()
+		defer rf.mu.Unlock()
+		if rf.file != nil {
+			err = rf.file.Close()
+			rf.file = nil
+		}
+	})
+	return err
+}
+
+func (rf *rotatingFile) triggerMill() {
+	select {
+	case rf.mill <- struct{}{}:
+	default:
+	}
+}
+
+func (rf *rotatingFile) runMill() {
+	for {
+		select {
+		case <-rf.mill:
+			err := rf.millRun()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[%s]:: failed to clean up rotated log files of '%s' : %v\n", PackageName, rf.path, err)
+			}
+		case <-rf.done:
+			return
+		}
+	}
+}
+
+type backupFile struct {
+	path      string
+	timestamp time.Time
+}
+
+// millRun compresses the rotated files and removes the ones which are out of the retention.
+func (rf *rotatingFile) millRun() error {
+	firstErr := rf.removeStaleTmpFiles()
+	backups, err := rf.backups()
+	if err != nil {
+		return err
+	}
+
+	keep := backups[:0]
+	for i, backup := range backups {
+		expired := rf.cfg.MaxAge > 0 && time.Since(backup.timestamp) > rf.cfg.MaxAge
+		exceeded := rf.cfg.MaxBackups > 0 && i >= rf.cfg.MaxBackups
+		if expired || exceeded {
+			err = os.Remove(backup.path)
+			if err != nil && !os.IsNotExist(err) && firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		keep = append(keep, backup)
+	}
+
+	if !rf.cfg.Compress {
+		return firstErr
+	}
+	for _, backup := range keep {
+		if strings.HasSuffix(backup.path, compressSuffix) {
+			continue
+		}
+		err = compressFile(backup.path)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// removeStaleTmpFiles removes the temporary files left by a compression which was interrupted, e.g. by a crash.
+func (rf *rotatingFile) removeStaleTmpFiles() error {
+	dir, prefix, _ := rf.nameParts()
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, compressTmpSuffix) {
+			continue
+		}
+		err = os.Remove(filepath.Join(dir, name))
+		if err != nil && !os.IsNotExist(err) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// backups returns the rotated files ordered from the newest.
+func (rf *rotatingFile) backups() ([]backupFile, error) {
+	dir, prefix, ext := rf.nameParts()
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		timestamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressSuffix), ext)
+		t, err := time.ParseInLocation(backupTimeFormat, timestamp, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), timestamp: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].timestamp.After(backups[j].timestamp)
+	})
+	return backups, nil
+}
+
+// compressFile writes path.gz through a temporary file, the original is removed when the compressed file is complete.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmpPath := path + compressTmpSuffix
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, _defaultFileMode)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	err = os.Rename(tmpPath, path+compressSuffix)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,180 @@
+package logx
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	stdoutSink = "stdout"
+	stderrSink = "stderr"
+	fileSink   = "file"
+	syslogSink = "syslog"
+
+	logfmtLogFormat = "logfmt"
+)
+
+/*
+	SinkCfg - is an output of the logger with its own minimum level and format (text, json or logfmt). Level is the
+			  LogLevel of LogCfg if it is empty, SetLogLevel changes only the sinks without their own Level. Sinks
+			  are written by the native encoders, LogBackend is not used.
+	For example;
+		sinks:
+			- type: stdout
+			  level: info
+			  format: text
+			- type: file
+			  level: debug
+			  format: json
+			  file: /var/log/app/app.log
+			  rotation_setting:
+				max_size_mb: 100
+			- type: syslog
+			  level: warning
+			  format: logfmt
+			  syslog_network: udp
+			  syslog_address: "localhost:514"
+			  syslog_tag: app
+*/
+type SinkCfg struct {
+	Type            string      `mapstructure:"type" json:"type"` // stdout, stderr, file or syslog
+	Level           string      `mapstructure:"level" json:"level"`
+	Format          string      `mapstructure:"format" json:"format"`
+	FilePath        string      `mapstructure:"file" json:"file"`
+	RotationSetting RotationCfg `mapstructure:"rotation_setting" json:"rotation_setting"`
+	SyslogNetwork   string      `mapstructure:"syslog_network" json:"syslog_network"` // empty to connect to local syslog daemon
+	SyslogAddress   string      `mapstructure:"syslog_address" json:"syslog_address"`
+	SyslogTag       string      `mapstructure:"syslog_tag" json:"syslog_tag"`
+}
+
+type multiHandler struct {
+	handlers []Handler
+}
+
+// NewMultiHandler writes each record to all the handlers which enable the level of the record.
+func NewMultiHandler(handlers ...Handler) Handler {
+	return &multiHandler{handlers: handlers}
+}
+
+func (h *multiHandler) Enabled(level Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *multiHandler) Handle(ctx context.Context, level Level, msg string, fields KV) error {
+	var firstErr error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(level) {
+			continue
+		}
+		err := handler.Handle(ctx, level, msg, fields)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// SetLevel sets the level of all the handlers.
+func (h *multiHandler) SetLevel(level Level) {
+	for _, handler := range h.handlers {
+		handler.SetLevel(level)
+	}
+}
+
+// fixedLevelHandler keeps the level of a sink configured with its own level when the level of the logger is changed.
+type fixedLevelHandler struct {
+	Handler
+}
+
+func (h fixedLevelHandler) SetLevel(Level) {}
+
+// sink is the handler of a SinkCfg with its async writer and the file to be closed, if any.
+type sink struct {
+	handler Handler
+	async   *asyncWriter
+	closer  io.Closer
+}
+
+func newSink(cfg SinkCfg, defaultLevel Level, asyncCfg AsyncCfg) (*sink, error) {
+	level := defaultLevel
+	if cfg.Level != "" {
+		var err error
+		level, err = ParseLevel(cfg.Level)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	encode, err := sinkEncoder(cfg.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sink{}
+	var w io.Writer
+	var output func(Level, []byte) error
+	switch strings.ToLower(cfg.Type) {
+	case stdoutSink, "":
+		w = os.Stdout
+	case stderrSink:
+		w = os.Stderr
+	case fileSink:
+		if cfg.FilePath == "" {
+			return nil, NewInvalidSinkError(cfg.Type, "file is required")
+		}
+		var file io.WriteCloser
+		if cfg.RotationSetting.enabled() {
+			file, err = newRotatingFile(cfg.FilePath, cfg.RotationSetting)
+		} else {
+			file, err = os.OpenFile(cfg.FilePath, _defaultFileFlag, _defaultFileMode)
+		}
+		if err != nil {
+			return nil, err
+		}
+		w = file
+		s.closer = file
+	case syslogSink:
+		output, s.closer, err = newSyslogOutput(cfg)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, NewInvalidSinkError(cfg.Type, "unsupported sink type")
+	}
+
+	switch {
+	case asyncCfg.Enabled && w != nil:
+		s.async = newAsyncWriter(asyncCfg, w)
+		output = s.async.output
+	case asyncCfg.Enabled:
+		s.async = newAsyncOutput(asyncCfg, output)
+		output = s.async.output
+	case w != nil:
+		output = writerOutput(w)
+	}
+	s.handler = newEncodingHandler(encode, output, level)
+	if cfg.Level != "" {
+		s.handler = fixedLevelHandler{s.handler}
+	}
+	return s, nil
+}
+
+func sinkEncoder(format string) (recordEncoder, error) {
+	switch strings.ToLower(format) {
+	case textLogFormat, "":
+		return encodeText, nil
+	case jsonLogFormat:
+		return encodeJSON, nil
+	case logfmtLogFormat:
+		return encodeLogfmt, nil
+	default:
+		return nil, NewInvalidSinkError(format, "unsupported log format")
+	}
+}
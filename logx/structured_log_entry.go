@@ -6,122 +6,122 @@ import (
 	"math"
 	"net/http"
 	"time"
-
-	"github.com/sirupsen/logrus"
 )
 
 type discardLoggingKey struct{}
+
+// StructuredLoggerEntry is the request log entry of chi RequestLogger, its fields are added to every log of the request.
 type StructuredLoggerEntry struct {
-	logger logrus.FieldLogger
+	handler Handler
+	fields  KV
+}
+
+func (l *StructuredLoggerEntry) log(ctx context.Context, level Level, err error, kv KV, msg string) {
+	if !l.handler.Enabled(level) {
+		return
+	}
+	l.handler.Handle(ctx, level, msg, mergeFields(l, err, kv))
+}
+
+// with returns a copy of the entry with the fields added, entries are shared by the logs of the request
+func (l *StructuredLoggerEntry) with(kv KV) *StructuredLoggerEntry {
+	fields := make(KV, len(l.fields)+len(kv))
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	for k, v := range kv {
+		fields[k] = v
+	}
+	return &StructuredLoggerEntry{handler: l.handler, fields: fields}
 }
 
 func (l *StructuredLoggerEntry) Error(ctx context.Context, err error, args ...interface{}) {
-	l.logger.WithFields(getErrorFields(err)).Errorln(args...)
+	l.log(ctx, ErrorLevel, err, nil, sprintln(args...))
 }
 
 func (l *StructuredLoggerEntry) Warn(ctx context.Context, args ...interface{}) {
-	l.logger.Warnln(args...)
+	l.log(ctx, WarnLevel, nil, nil, sprintln(args...))
 }
 
 func (l *StructuredLoggerEntry) Info(ctx context.Context, args ...interface{}) {
-	l.logger.Infoln(args...)
+	l.log(ctx, InfoLevel, nil, nil, sprintln(args...))
 }
 
 func (l *StructuredLoggerEntry) Debug(ctx context.Context, args ...interface{}) {
-	l.logger.Debugln(args...)
+	l.log(ctx, DebugLevel, nil, nil, sprintln(args...))
 }
 
 func (l *StructuredLoggerEntry) Errorf(ctx context.Context, err error, message string, args ...interface{}) {
-	l.logger.WithFields(getErrorFields(err)).Errorf(message, args...)
+	l.log(ctx, ErrorLevel, err, nil, fmt.Sprintf(message, args...))
 }
 
 func (l *StructuredLoggerEntry) Warnf(ctx context.Context, message string, args ...interface{}) {
-	l.logger.Warnf(message, args...)
+	l.log(ctx, WarnLevel, nil, nil, fmt.Sprintf(message, args...))
 }
 
 func (l *StructuredLoggerEntry) Infof(ctx context.Context, message string, args ...interface{}) {
-	l.logger.Infof(message, args...)
+	l.log(ctx, InfoLevel, nil, nil, fmt.Sprintf(message, args...))
 }
 
 func (l *StructuredLoggerEntry) Debugf(ctx context.Context, message string, args ...interface{}) {
-	l.logger.Debugf(message, args...)
+	l.log(ctx, DebugLevel, nil, nil, fmt.Sprintf(message, args...))
 }
 
 func (l *StructuredLoggerEntry) ErrorKV(ctx context.Context, err error, kv KV, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	errorFields := getErrorFields(err)
-	for k, v := range errorFields {
-		fields[k] = v
-	}
-	l.logger.WithFields(fields).Errorln(args...)
+	l.log(ctx, ErrorLevel, err, kv, sprintln(args...))
 }
 
 func (l *StructuredLoggerEntry) WarnKV(ctx context.Context, kv KV, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	l.logger.WithFields(fields).Warnln(args...)
+	l.log(ctx, WarnLevel, nil, kv, sprintln(args...))
 }
 
 func (l *StructuredLoggerEntry) InfoKV(ctx context.Context, kv KV, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	l.logger.WithFields(fields).Info(args...)
+	l.log(ctx, InfoLevel, nil, kv, sprintln(args...))
 }
 
 func (l *StructuredLoggerEntry) DebugKV(ctx context.Context, kv KV, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	l.logger.WithFields(fields).Debug(args...)
+	l.log(ctx, DebugLevel, nil, kv, sprintln(args...))
 }
 
 func (l *StructuredLoggerEntry) ErrorKVf(ctx context.Context, err error, kv KV, message string, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	errorFields := getErrorFields(err)
-	for k, v := range errorFields {
-		fields[k] = v
-	}
-	l.logger.WithFields(fields).Errorf(message, args...)
+	l.log(ctx, ErrorLevel, err, kv, fmt.Sprintf(message, args...))
 }
 
 func (l *StructuredLoggerEntry) WarnKVf(ctx context.Context, kv KV, message string, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	l.logger.WithFields(fields).Warnf(message, args...)
+	l.log(ctx, WarnLevel, nil, kv, fmt.Sprintf(message, args...))
 }
 
 func (l *StructuredLoggerEntry) InfoKVf(ctx context.Context, kv KV, message string, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	l.logger.WithFields(fields).Infof(message, args...)
+	l.log(ctx, InfoLevel, nil, kv, fmt.Sprintf(message, args...))
 }
 
 func (l *StructuredLoggerEntry) DebugKVf(ctx context.Context, kv KV, message string, args ...interface{}) {
-	fields := convertKvsToLogrusFields(kv)
-	l.logger.WithFields(fields).Debugf(message, args...)
+	l.log(ctx, DebugLevel, nil, kv, fmt.Sprintf(message, args...))
 }
 
 func (l *StructuredLoggerEntry) Write(status, bytes int, header http.Header, elapsed time.Duration, data interface{}) {
-	l.logger = l.logger.WithFields(logrus.Fields{
+	l.log(context.Background(), InfoLevel, nil, KV{
 		"resp_status": status, "resp_bytes_length": bytes,
 		"resp_elapsed_ms": float64(elapsed.Nanoseconds()) / 1000000.0,
-	})
-
-	l.logger.Infoln("request complete")
+	}, "request complete")
 }
 
 func (l *StructuredLoggerEntry) WriteError(status, bytes int, elapsed time.Duration) {
-	l.logger = l.logger.WithFields(logrus.Fields{
+	l.log(context.Background(), ErrorLevel, nil, KV{
 		"resp_status": status, "resp_bytes_length": bytes,
 		"resp_elapsed_ms": int(math.Ceil(float64(elapsed.Nanoseconds()) / 1000000.0)),
-	})
-	l.logger.Errorln("request complete")
+	}, "request complete")
 }
 
 func (l *StructuredLoggerEntry) WriteWarn(status, bytes int, elapsed time.Duration) {
-	l.logger = l.logger.WithFields(logrus.Fields{
+	l.log(context.Background(), WarnLevel, nil, KV{
 		"resp_status": status, "resp_bytes_length": bytes,
 		"resp_elapsed_ms": int(math.Ceil(float64(elapsed.Nanoseconds()) / 1000000.0)),
-	})
-	l.logger.Warningln("request complete")
+	}, "request complete")
 }
 
 func (l *StructuredLoggerEntry) Panic(v interface{}, stack []byte) {
-	l.logger = l.logger.WithFields(logrus.Fields{
+	*l = *l.with(KV{
 		"stack": string(stack),
 		"panic": fmt.Sprintf("%+v", v),
 	})
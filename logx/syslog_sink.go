@@ -0,0 +1,31 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logx
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// newSyslogOutput writes the records with the syslog priority of their level.
+func newSyslogOutput(cfg SinkCfg) (func(Level, []byte) error, io.Closer, error) {
+	writer, err := syslog.Dial(cfg.SyslogNetwork, cfg.SyslogAddress, syslog.LOG_INFO|syslog.LOG_USER, cfg.SyslogTag)
+	if err != nil {
+		return nil, nil, err
+	}
+	output := func(level Level, p []byte) error {
+		msg := string(p)
+		switch level {
+		case DebugLevel:
+			return writer.Debug(msg)
+		case WarnLevel:
+			return writer.Warning(msg)
+		case ErrorLevel:
+			return writer.Err(msg)
+		default:
+			return writer.Info(msg)
+		}
+	}
+	return output, writer, nil
+}
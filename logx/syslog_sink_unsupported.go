@@ -0,0 +1,10 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package logx
+
+import "io"
+
+func newSyslogOutput(cfg SinkCfg) (func(Level, []byte) error, io.Closer, error) {
+	return nil, nil, NewInvalidSinkError(cfg.Type, "syslog is not supported on this platform")
+}
@@ -0,0 +1,102 @@
+package logx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const textTimeFormat string = "2006-01-02T15:04:05.000Z07:00"
+
+/*
+	NewTextHandler - writes human readable lines for console, e.g.
+						2020-06-01T10:00:00.000Z INFO  request served user_id=42 path=/users/42
+*/
+func NewTextHandler(w io.Writer, level Level) Handler {
+	return newEncodingHandler(encodeText, writerOutput(w), level)
+}
+
+/*
+	NewLogfmtHandler - writes logfmt lines, e.g.
+						time=2020-06-01T10:00:00Z level=info msg="request served" user_id=42
+*/
+func NewLogfmtHandler(w io.Writer, level Level) Handler {
+	return newEncodingHandler(encodeLogfmt, writerOutput(w), level)
+}
+
+func encodeText(buf []byte, t time.Time, level Level, msg string, keys []string, fields KV) []byte {
+	buf = t.AppendFormat(buf, textTimeFormat)
+	buf = append(buf, ' ')
+	levelName := strings.ToUpper(level.String())
+	if len(levelName) > 4 {
+		levelName = levelName[:4]
+	}
+	buf = append(buf, levelName...)
+	for i := len(levelName); i < 5; i++ {
+		buf = append(buf, ' ')
+	}
+	buf = append(buf, ' ')
+	buf = append(buf, msg...)
+	for _, key := range keys {
+		buf = append(buf, ' ')
+		buf = append(buf, key...)
+		buf = append(buf, '=')
+		buf = appendLogfmtValue(buf, fields[key])
+	}
+	return append(buf, '\n')
+}
+
+func encodeLogfmt(buf []byte, t time.Time, level Level, msg string, keys []string, fields KV) []byte {
+	buf = append(buf, "time="...)
+	buf = t.AppendFormat(buf, time.RFC3339Nano)
+	buf = append(buf, " level="...)
+	buf = append(buf, level.String()...)
+	buf = append(buf, " msg="...)
+	buf = appendLogfmtString(buf, msg)
+	for _, key := range keys {
+		buf = append(buf, ' ')
+		if key == "time" || key == "level" || key == "msg" {
+			buf = append(buf, "fields."...)
+		}
+		buf = append(buf, key...)
+		buf = append(buf, '=')
+		buf = appendLogfmtValue(buf, fields[key])
+	}
+	return append(buf, '\n')
+}
+
+func appendLogfmtValue(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return buf
+	case string:
+		return appendLogfmtString(buf, v)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return appendJSONValue(buf, v)
+	case time.Time:
+		return v.AppendFormat(buf, time.RFC3339Nano)
+	case time.Duration:
+		return append(buf, v.String()...)
+	case error:
+		return appendLogfmtString(buf, v.Error())
+	case fmt.Stringer:
+		return appendLogfmtString(buf, v.String())
+	default:
+		// nested values are written as quoted JSON
+		return appendJSONString(buf, string(appendJSONValue(nil, v)))
+	}
+}
+
+// appendLogfmtString quotes the value only when it contains space, quote, '=' or control characters.
+func appendLogfmtString(buf []byte, s string) []byte {
+	if s == "" {
+		return append(buf, `""`...)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] <= ' ' || s[i] == '=' || s[i] == '"' || s[i] == '\\' || s[i] >= 0x7f {
+			return appendJSONString(buf, s)
+		}
+	}
+	return append(buf, s...)
+}
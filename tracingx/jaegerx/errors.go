@@ -0,0 +1,47 @@
+package jaegerx
+
+import (
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// SetErrorTags marks the span as failed and sets message, code, ID and attributes of the error as span tags.
+func SetErrorTags(span opentracing.Span, err error) {
+	if span == nil || err == nil {
+		return
+	}
+
+	ext.Error.Set(span, true)
+	span.SetTag("error.message", errorx.GetErrorMessages(err))
+	code, id := errorCodeAndID(err)
+	if code != 0 {
+		span.SetTag("error.code", code)
+	}
+	if id != "" {
+		span.SetTag("error.id", id)
+	}
+	for _, attr := range errorx.AllAttributes(err) {
+		span.SetTag("error."+attr.Key, attr.Value)
+	}
+}
+
+// errorCodeAndID returns the first code and the first ID in the chain, so that wrapped catalog errors are tagged as well.
+func errorCodeAndID(err error) (int, string) {
+	var (
+		code int
+		id   string
+	)
+	errorx.Walk(err, func(e error) bool {
+		errWithCode, ok := e.(errorx.ErrorCode)
+		if ok && code == 0 {
+			code = errWithCode.Code()
+		}
+		errWithID, ok := e.(errorx.ErrorID)
+		if ok && id == "" {
+			id = errWithID.ID()
+		}
+		return code == 0 || id == ""
+	})
+	return code, id
+}
@@ -27,6 +27,13 @@ type JaegerTracer interface {
 	IsEnabled() bool
 }
 
+// SpanStarter - is implemented by the tracer of New, it is kept out of JaegerTracer so that existing implementations
+// of JaegerTracer do not have to implement it.
+type SpanStarter interface {
+	// StartSpan starts an internal span, e.g. to group several outgoing calls under one parent span.
+	StartSpan(context.Context, string) (opentracing.Span, context.Context)
+}
+
 type jaegerClient struct {
 	cfg    *JaegerCfg
 	tracer opentracing.Tracer
@@ -112,6 +119,14 @@ func (jaegerClient *jaegerClient) HttpClientTracer(ctx context.Context, req *htt
 	return span
 }
 
+// StartSpan starts an internal span, e.g. to group several outgoing calls under one parent span.
+func (jaegerClient *jaegerClient) StartSpan(ctx context.Context, operationName string) (opentracing.Span, context.Context) {
+	if !jaegerClient.cfg.Enabled {
+		return nil, ctx
+	}
+	return opentracing.StartSpanFromContextWithTracer(ctx, jaegerClient.tracer, operationName)
+}
+
 func (jaegerClient *jaegerClient) MiddlewareTracer(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !jaegerClient.cfg.Enabled {
@@ -1,6 +1,9 @@
 package newrelicx
 
-import "github.com/kyawmyintthein/orange-contrib/errorx"
+import (
+	"github.com/kyawmyintthein/orange-contrib/errorx"
+	newrelic "github.com/newrelic/go-agent"
+)
 
 type NotAvailable struct {
 	*errorx.ErrorX
@@ -11,3 +14,38 @@ func NotAvailableError() *NotAvailable {
 		errorx.NewErrorX("[%s] new-relic tracer is not avaliable", PackageName),
 	}
 }
+
+/*
+	NoticeError - records the error into the transaction with error ID as the class and error attributes,
+				  so that errors can be grouped and filtered by them in Newrelic.
+*/
+func NoticeError(txn newrelic.Transaction, err error) error {
+	if txn == nil || err == nil {
+		return nil
+	}
+
+	nrErr := newrelic.Error{
+		Message:    errorx.GetErrorMessages(err),
+		Attributes: make(map[string]interface{}),
+	}
+	// code and ID are taken from the first error in the chain which has them, e.g. a wrapped catalog error
+	var code int
+	errorx.Walk(err, func(e error) bool {
+		errWithID, ok := e.(errorx.ErrorID)
+		if ok && nrErr.Class == "" {
+			nrErr.Class = errWithID.ID()
+		}
+		errWithCode, ok := e.(errorx.ErrorCode)
+		if ok && code == 0 {
+			code = errWithCode.Code()
+		}
+		return code == 0 || nrErr.Class == ""
+	})
+	if code != 0 {
+		nrErr.Attributes["error.code"] = code
+	}
+	for _, attr := range errorx.AllAttributes(err) {
+		nrErr.Attributes["error."+attr.Key] = attr.Value
+	}
+	return txn.NoticeError(nrErr)
+}